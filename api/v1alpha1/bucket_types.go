@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,18 +27,89 @@ import (
 const Separator = "."
 
 // BucketSpec defines the desired state of Bucket.
+// +kubebuilder:validation:XValidation:rule="!has(self.credentialMode) || self.credentialMode != 'STS' || !has(self.credentialRotation)",message="credentialRotation does not apply to STS credentials"
+// +kubebuilder:validation:XValidation:rule="has(self.externalName) == has(oldSelf.externalName)",message="externalName cannot be added or removed, recreate the bucket"
+// +kubebuilder:validation:XValidation:rule="has(self.connectionRef) == has(oldSelf.connectionRef)",message="connectionRef cannot be added or removed, recreate the bucket"
 type BucketSpec struct {
 	SecretName string `json:"secretName"`
 
+	// CredentialRotation periodically regenerates the credentials of the Secret.
+	// +kubebuilder:validation:Optional
+	CredentialRotation *CredentialRotation `json:"credentialRotation,omitempty"`
+
+	// CredentialMode selects long-lived (Static, the default) or temporary (STS) credentials for the Secret.
+	// +kubebuilder:validation:Optional
+	CredentialMode CredentialMode `json:"credentialMode,omitempty"`
+
+	// STS configures the temporary credentials of the STS mode.
+	// +kubebuilder:validation:Optional
+	STS *STSCredentials `json:"sts,omitempty"`
+
+	// SecretTemplate renders extra keys into the Secret, e.g. the configuration of S3 clients.
+	// +kubebuilder:validation:Optional
+	SecretTemplate *SecretTemplate `json:"secretTemplate,omitempty"`
+
+	// ExternalName is the name of the bucket in MinIO, defaults to "<namespace>.<name>".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="externalName is immutable"
+	ExternalName string `json:"externalName,omitempty"`
+
+	// ConnectionRef selects the MinIO server hosting the bucket.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="connectionRef is immutable"
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+
+	// Adopt allows the resource to take over a bucket already existing in MinIO under ExternalName.
+	// A bucket managed by another resource is never adopted.
+	// +kubebuilder:validation:Optional
+	Adopt bool `json:"adopt,omitempty"`
+
 	// Specifies which policy is attached to the current bucket.
 	// Valid values are:
 	// - "private" (default): forbids anonymous user to perform any action on the bucket;
 	// - "public": allows any action of upload or download on the bucket for the anonymous user;
 	// - "upload": allows all the upload actions to the anonymous user on the bucket;
 	// - "download": allows all the download actions to the anonymous user on the bucket;
+	// When omitted on a bucket with an ExternalName, its current policy is left untouched.
+	// +kubebuilder:validation:Optional
+	Policy BucketPolicy `json:"policy,omitempty"`
+
+	// Versioning configures object versioning on the bucket.
+	// When omitted, the versioning state of the bucket is left untouched.
+	// +kubebuilder:validation:Optional
+	Versioning *BucketVersioning `json:"versioning,omitempty"`
+
+	// Lifecycle configures the lifecycle (ILM) rules applied to the objects of the bucket.
+	// When omitted, the lifecycle configuration of the bucket is left untouched,
+	// an empty list of rules removes it.
+	// +kubebuilder:validation:Optional
+	Lifecycle *BucketLifecycle `json:"lifecycle,omitempty"`
+
+	// Quota limits the amount of data stored in the bucket.
+	// When omitted, the quota of the bucket is left untouched.
+	// +kubebuilder:validation:Optional
+	Quota *BucketQuota `json:"quota,omitempty"`
+
+	// ObjectLock enables object locking (WORM) on the bucket.
+	// Object locking can only be enabled when the bucket is created and never disabled,
+	// afterwards only its default retention can be changed. When omitted, the object
+	// lock configuration of the bucket is left untouched and reported by the ObjectLock condition.
+	// +kubebuilder:validation:Optional
+	ObjectLock *BucketObjectLock `json:"objectLock,omitempty"`
+
+	// Encryption configures the server-side encryption applied by default to the objects of the bucket.
+	// When omitted, the encryption of the bucket is left untouched.
+	// +kubebuilder:validation:Optional
+	Encryption *BucketEncryption `json:"encryption,omitempty"`
+
+	// Specifies what happens to the MinIO bucket when the resource is deleted.
+	// Valid values are:
+	// - "Delete" (default): removes the bucket and its users, only if the bucket is empty;
+	// - "Retain": keeps the bucket and its users;
+	// - "ForceDelete": removes every object version stored in the bucket, then the bucket and its users;
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:default=private
-	Policy BucketPolicy `json:"policy"`
+	// +kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // BucketPolicy describes the policy attached to the bucket for the anonymous user to use.
@@ -62,9 +134,215 @@ const (
 	PolicyDownload BucketPolicy = "download"
 )
 
+// BucketVersioning describes the versioning configuration of a bucket.
+type BucketVersioning struct {
+	// Status of the versioning on the bucket.
+	// Once enabled, versioning can only be suspended, never removed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Enabled
+	Status VersioningStatus `json:"status"`
+
+	// Object prefixes excluded from versioning, only applies when versioning is enabled.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=set
+	ExcludedPrefixes []string `json:"excludedPrefixes,omitempty"`
+
+	// Disables versioning of folder objects (objects ending with "/").
+	// +kubebuilder:validation:Optional
+	ExcludeFolders bool `json:"excludeFolders,omitempty"`
+}
+
+// VersioningStatus is the state of the versioning on a bucket.
+// +kubebuilder:validation:Enum=Enabled;Suspended
+type VersioningStatus string
+
+const (
+	// VersioningEnabled keeps every version of the objects stored in the bucket.
+	VersioningEnabled VersioningStatus = "Enabled"
+
+	// VersioningSuspended stops creating new versions, existing ones are kept.
+	VersioningSuspended VersioningStatus = "Suspended"
+)
+
+// BucketLifecycle describes the lifecycle configuration of a bucket.
+type BucketLifecycle struct {
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=id
+	Rules []LifecycleRule `json:"rules,omitempty"`
+}
+
+// LifecycleRule is a single lifecycle rule, it selects objects through its filter
+// and applies every action set on them.
+// +kubebuilder:validation:XValidation:rule="has(self.expirationDays) || has(self.noncurrentVersionExpirationDays) || has(self.abortIncompleteMultipartUploadDays)",message="at least one action must be set"
+type LifecycleRule struct {
+	// Unique identifier of the rule inside the bucket.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=255
+	ID string `json:"id"`
+
+	// Disables the rule without removing it from the configuration.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Only objects with a key starting with this prefix are affected by the rule.
+	// +kubebuilder:validation:Optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Only objects carrying all those tags are affected by the rule.
+	// +kubebuilder:validation:Optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Number of days after creation when the current version of objects expires.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	ExpirationDays int32 `json:"expirationDays,omitempty"`
+
+	// Number of days after becoming noncurrent when versions of objects expire.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	NoncurrentVersionExpirationDays int32 `json:"noncurrentVersionExpirationDays,omitempty"`
+
+	// Number of the most recent noncurrent versions kept regardless of their age.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	NewerNoncurrentVersions int32 `json:"newerNoncurrentVersions,omitempty"`
+
+	// Number of days after initiation when incomplete multipart uploads are aborted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	AbortIncompleteMultipartUploadDays int32 `json:"abortIncompleteMultipartUploadDays,omitempty"`
+}
+
+// BucketQuota describes the hard quota applied to a bucket.
+type BucketQuota struct {
+	// Maximum size of the bucket, writes beyond it are rejected.
+	// A zero size removes the quota.
+	// +kubebuilder:validation:Required
+	Size resource.Quantity `json:"size"`
+
+	// Percentage of the quota above which the QuotaWarning condition is raised,
+	// defaults to DefaultQuotaWarningPercentage.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	WarningPercentage int32 `json:"warningPercentage,omitempty"`
+}
+
+// DefaultQuotaWarningPercentage is the warning percentage of a quota omitting it.
+const DefaultQuotaWarningPercentage = 80
+
+// WarningPercentageOrDefault returns the warning percentage of the quota.
+func (q BucketQuota) WarningPercentageOrDefault() int32 {
+	if q.WarningPercentage == 0 {
+		return DefaultQuotaWarningPercentage
+	}
+	return q.WarningPercentage
+}
+
+// BucketObjectLock describes the object locking configuration of a bucket.
+type BucketObjectLock struct {
+	// Retention applied by default to the objects written to the bucket,
+	// when omitted objects are only retained when explicitly requested.
+	// +kubebuilder:validation:Optional
+	DefaultRetention *ObjectLockRetention `json:"defaultRetention,omitempty"`
+}
+
+// ObjectLockRetention describes how long and how strictly objects are retained.
+// +kubebuilder:validation:XValidation:rule="has(self.days) != has(self.years)",message="exactly one of days or years must be set"
+type ObjectLockRetention struct {
+	// +kubebuilder:validation:Required
+	Mode RetentionMode `json:"mode"`
+
+	// Number of days objects are retained.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Days int32 `json:"days,omitempty"`
+
+	// Number of years objects are retained.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Years int32 `json:"years,omitempty"`
+}
+
+// RetentionMode describes the protection applied to retained objects.
+// +kubebuilder:validation:Enum=GOVERNANCE;COMPLIANCE
+type RetentionMode string
+
+const (
+	// RetentionGovernance prevents deletion of retained objects, unless the user
+	// has been granted the s3:BypassGovernanceRetention permission.
+	RetentionGovernance RetentionMode = "GOVERNANCE"
+
+	// RetentionCompliance prevents deletion of retained objects by any user, including root.
+	RetentionCompliance RetentionMode = "COMPLIANCE"
+)
+
+// BucketEncryption describes the default server-side encryption of a bucket.
+// +kubebuilder:validation:XValidation:rule="self.algorithm == 'SSE-KMS' || !has(self.kmsKeyID)",message="kmsKeyID is only valid with SSE-KMS"
+type BucketEncryption struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=SSE-S3
+	Algorithm EncryptionAlgorithm `json:"algorithm"`
+
+	// Name of the KMS key used to encrypt objects, the default key of the KMS is used when omitted.
+	// +kubebuilder:validation:Optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+}
+
+// EncryptionAlgorithm describes how the objects of a bucket are encrypted.
+// Both algorithms require a KMS to be configured on the MinIO server.
+// +kubebuilder:validation:Enum=SSE-S3;SSE-KMS
+type EncryptionAlgorithm string
+
+const (
+	// EncryptionSSES3 encrypts objects with keys managed by the MinIO server.
+	EncryptionSSES3 EncryptionAlgorithm = "SSE-S3"
+
+	// EncryptionSSEKMS encrypts objects with a named key stored in the KMS.
+	EncryptionSSEKMS EncryptionAlgorithm = "SSE-KMS"
+)
+
+// DeletionPolicy describes what happens to the MinIO bucket when the resource is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain;ForceDelete
+type DeletionPolicy string
+
+const (
+	// DeletionDelete removes the bucket and its users, deletion is blocked until the bucket is empty.
+	DeletionDelete DeletionPolicy = "Delete"
+
+	// DeletionRetain keeps the bucket and its users, only the resource is removed.
+	DeletionRetain DeletionPolicy = "Retain"
+
+	// DeletionForceDelete purges all the object versions of the bucket before removing it.
+	DeletionForceDelete DeletionPolicy = "ForceDelete"
+)
+
 // BucketStatus defines the observed state of Bucket.
 type BucketStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// BucketName is the name of the bucket in MinIO, resolved once from the naming
+	// strategy of the controller and kept afterwards.
+	BucketName string `json:"bucketName,omitempty"`
+
+	// Versioning is the versioning status in effect on the bucket,
+	// empty when versioning was never enabled.
+	Versioning VersioningStatus `json:"versioning,omitempty"`
+
+	// Quota is the hard quota in effect on the bucket.
+	Quota *resource.Quantity `json:"quota,omitempty"`
+
+	// Usage is the amount of data stored in the bucket, as last reported by MinIO.
+	Usage *resource.Quantity `json:"usage,omitempty"`
+
+	// PurgedObjects is the number of object versions removed so far
+	// while force deleting the bucket.
+	PurgedObjects int64 `json:"purgedObjects,omitempty"`
+
+	// Credentials records the rotations of the credentials of the Secret.
+	Credentials *CredentialsStatus `json:"credentials,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -79,10 +357,23 @@ type Bucket struct {
 	Status BucketStatus `json:"status,omitempty"`
 }
 
+// BucketName returns the name of the bucket in MinIO, the one recorded in the status
+// prevails so that changing the naming strategy never orphans a bucket.
 func (m Bucket) BucketName() string {
+	if m.Status.BucketName != "" {
+		return m.Status.BucketName
+	}
+	if m.Spec.ExternalName != "" {
+		return m.Spec.ExternalName
+	}
 	return m.Namespace + Separator + m.Name
 }
 
+// Owner returns the identity recorded on the MinIO bucket to claim it.
+func (m Bucket) Owner() string {
+	return m.Namespace + "/" + m.Name
+}
+
 // +kubebuilder:object:root=true
 
 // BucketList contains a list of Bucket.
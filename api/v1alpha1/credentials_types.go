@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationRotateCredentials forces a rotation of the generated credentials,
+// a new rotation is triggered each time its value changes.
+const AnnotationRotateCredentials = "minio.ixday.github.io/rotate-credentials"
+
+// CredentialRotation periodically regenerates the credentials stored in the Secret.
+// +kubebuilder:validation:XValidation:rule="duration(self.interval) > duration('0s')",message="interval must be positive"
+// +kubebuilder:validation:XValidation:rule="!has(self.overlap) || duration(self.overlap) < duration(self.interval)",message="overlap must be shorter than interval"
+type CredentialRotation struct {
+	// Interval between two rotations, e.g. "720h".
+	// +kubebuilder:validation:Required
+	Interval metav1.Duration `json:"interval"`
+
+	// Overlap keeps the previous credentials valid for this duration after a rotation,
+	// they are moved to a service account of the new user which expires afterwards.
+	// The access key is regenerated along with the secret key when set, the spec.accessKeys
+	// of a Policy are then recreated under the new user and refused meanwhile.
+	// +kubebuilder:validation:Optional
+	Overlap *metav1.Duration `json:"overlap,omitempty"`
+}
+
+// CredentialMode selects how the credentials of the Secret are issued.
+// +kubebuilder:validation:Enum=Static;STS
+type CredentialMode string
+
+const (
+	// CredentialModeStatic stores the long-lived credentials of the MinIO user in the Secret.
+	CredentialModeStatic CredentialMode = "Static"
+
+	// CredentialModeSTS stores temporary credentials issued by AssumeRole for the MinIO user
+	// in the accessKey, secretKey, sessionToken and expiration keys of the Secret. They are
+	// refreshed before they expire, the password of the user is reset before each refresh
+	// and never stored.
+	CredentialModeSTS CredentialMode = "STS"
+)
+
+// STSCredentials configures the temporary credentials of the STS mode.
+type STSCredentials struct {
+	// Duration is the validity of the credentials, they are refreshed once two thirds of it elapsed.
+	// MinIO issues temporary credentials for 7 days at most.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1h"
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1h')",message="duration must be at least 1h"
+	// +kubebuilder:validation:XValidation:rule="duration(self) <= duration('168h')",message="duration must be at most 168h"
+	Duration metav1.Duration `json:"duration,omitempty"`
+}
+
+// CredentialsStatus records the rotations of the generated credentials.
+type CredentialsStatus struct {
+	// LastRotationTime is when the credentials were last generated.
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// NextRotationTime is when the credentials are due for rotation.
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+
+	// PreviousExpirationTime is when the previous credentials stop working,
+	// only set during the overlap following a rotation.
+	PreviousExpirationTime *metav1.Time `json:"previousExpirationTime,omitempty"`
+
+	// ExpirationTime is when the temporary credentials of the Secret expire, only set in STS mode.
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// RotationRequest is the last value of the rotate-credentials annotation handled.
+	RotationRequest string `json:"rotationRequest,omitempty"`
+}
+
+// SecretPreset renders a well known credentials format into the Secret.
+// +kubebuilder:validation:Enum=aws-env;rclone;s3cmd;s3-url
+type SecretPreset string
+
+const (
+	// PresetAWSEnv adds the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_REGION,
+	// AWS_ENDPOINT_URL and BUCKET_NAME environment variables.
+	PresetAWSEnv SecretPreset = "aws-env"
+
+	// PresetRclone adds an "rclone.conf" file defining a "minio" remote.
+	PresetRclone SecretPreset = "rclone"
+
+	// PresetS3cmd adds a ".s3cfg" configuration file.
+	PresetS3cmd SecretPreset = "s3cmd"
+
+	// PresetS3URL adds S3_URL, an s3:// URL of the bucket embedding the credentials.
+	PresetS3URL SecretPreset = "s3-url"
+)
+
+// SecretTemplate renders extra keys into the Secret from the credentials and the location of the bucket,
+// the "user" and "password" keys are always managed by the controller.
+type SecretTemplate struct {
+	// Presets render well known credentials formats.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	Presets []SecretPreset `json:"presets,omitempty"`
+
+	// Data maps keys of the Secret to Go templates, .Endpoint, .Host, .Secure, .Region,
+	// .Bucket, .User, .Password and .SessionToken are available. They take precedence over the presets.
+	// In STS mode .User and .Password are the temporary access and secret keys.
+	// +kubebuilder:validation:Optional
+	Data map[string]string `json:"data,omitempty"`
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GroupSpec defines the desired state of Group.
+// +kubebuilder:validation:XValidation:rule="has(self.connectionRef) == has(oldSelf.connectionRef)",message="connectionRef cannot be added or removed, recreate the group"
+type GroupSpec struct {
+	// Policies are the names of the Policy resources of the namespace attached to the group,
+	// their members inherit the permissions of all of them.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	Policies []string `json:"policies,omitempty"`
+
+	// Members are the users of the group.
+	// +kubebuilder:validation:Optional
+	Members []GroupMember `json:"members,omitempty"`
+
+	// ConnectionRef selects the MinIO server hosting the group, its policies and members must live there:
+	// a referenced Policy of another connection is refused with the ConnectionMismatch reason.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="connectionRef is immutable"
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+}
+
+// GroupMember is a user of the group, either generated for a Policy or managed outside of the controller.
+// +kubebuilder:validation:XValidation:rule="has(self.policyRef) != has(self.user)",message="exactly one of policyRef or user must be set"
+type GroupMember struct {
+	// PolicyRef is the name of a Policy resource of the namespace, its generated user joins the group.
+	// +kubebuilder:validation:Optional
+	PolicyRef string `json:"policyRef,omitempty"`
+
+	// User is the name of a MinIO user managed outside of the controller.
+	// +kubebuilder:validation:Optional
+	User string `json:"user,omitempty"`
+}
+
+// GroupStatus defines the observed state of Group.
+type GroupStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// Members are the MinIO users of the group.
+	Members []string `json:"members,omitempty"`
+
+	// Policies are the canned policies attached to the group.
+	Policies []string `json:"policies,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Group is the Schema for the groups API.
+type Group struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GroupSpec   `json:"spec,omitempty"`
+	Status GroupStatus `json:"status,omitempty"`
+}
+
+// GroupName is the name of the group in MinIO.
+func (g Group) GroupName() string {
+	return g.Namespace + Separator + g.Name
+}
+
+// +kubebuilder:object:root=true
+
+// GroupList contains a list of Group.
+type GroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Group `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Group{}, &GroupList{})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// KindMinioConnection is the kind of the namespaced connections.
+	KindMinioConnection = "MinioConnection"
+	// KindClusterMinioConnection is the kind of the cluster wide connections.
+	KindClusterMinioConnection = "ClusterMinioConnection"
+)
+
+// MinioConnectionSpec defines how to reach a MinIO server.
+type MinioConnectionSpec struct {
+	// Endpoint of the MinIO server as host[:port], without scheme.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[^/:]+(:[0-9]+)?$`
+	Endpoint string `json:"endpoint"`
+
+	// CredentialsSecretRef references the Secret holding the "user" and "password"
+	// of an administrator of the MinIO server.
+	// +kubebuilder:validation:Required
+	CredentialsSecretRef SecretReference `json:"credentialsSecretRef"`
+
+	// TLS enables HTTPS towards the endpoint, plain HTTP is used when omitted.
+	// +kubebuilder:validation:Optional
+	TLS *ConnectionTLS `json:"tls,omitempty"`
+
+	// Region in which the buckets are created.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=us-east-1
+	Region string `json:"region,omitempty"`
+}
+
+// SecretReference locates a Secret.
+type SecretReference struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace of the Secret, only honored by a ClusterMinioConnection where it
+	// defaults to the namespace of the controller. A MinioConnection always uses its own namespace.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ConnectionTLS configures the verification of the certificate presented by the server
+// and the certificate presented by the controller.
+type ConnectionTLS struct {
+	// CASecretRef references a Secret holding the "ca.crt" bundle trusted to sign the
+	// server certificate, the system roots are used when omitted.
+	// The Secret is read in the same namespace as the credentials.
+	// +kubebuilder:validation:Optional
+	CASecretRef *SecretReference `json:"caSecretRef,omitempty"`
+
+	// ClientCertSecretRef references a kubernetes.io/tls Secret holding the "tls.crt" and
+	// "tls.key" presented to the server for mutual TLS.
+	// The Secret is read in the same namespace as the credentials.
+	// +kubebuilder:validation:Optional
+	ClientCertSecretRef *SecretReference `json:"clientCertSecretRef,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the server certificate.
+	// +kubebuilder:validation:Optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// ConnectionReference selects the connection used to manage a resource,
+// the connection configured on the controller is used when omitted.
+// Once the connection is deleted, the resources referencing it are released
+// on deletion without removing anything from MinIO.
+type ConnectionReference struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=MinioConnection;ClusterMinioConnection
+	// +kubebuilder:default=MinioConnection
+	Kind string `json:"kind,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// MinioConnectionStatus defines the observed state of a connection.
+type MinioConnectionStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.spec.endpoint`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+
+// MinioConnection is the Schema for the minioconnections API.
+type MinioConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Spec   MinioConnectionSpec   `json:"spec"`
+	Status MinioConnectionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MinioConnectionList contains a list of MinioConnection.
+type MinioConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MinioConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MinioConnection{}, &MinioConnectionList{})
+}
@@ -21,6 +21,11 @@ import (
 )
 
 // PolicySpec defines the desired state of Policy.
+// +kubebuilder:validation:XValidation:rule="has(self.statements) != has(self.document)",message="exactly one of statements or document must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.credentialMode) || self.credentialMode != 'STS' || !has(self.credentialRotation)",message="credentialRotation does not apply to STS credentials"
+// +kubebuilder:validation:XValidation:rule="!has(self.identities) || !(has(self.credentialRotation) || has(self.sts) || has(self.secretTemplate) || has(self.accessKeys) || (has(self.credentialMode) && self.credentialMode == 'STS') || (has(self.secretName) && size(self.secretName) > 0))",message="identities replace the generated user, its credentials cannot be configured"
+// +kubebuilder:validation:XValidation:rule="has(self.identities) == has(oldSelf.identities)",message="identities cannot be added or removed, recreate the policy"
+// +kubebuilder:validation:XValidation:rule="has(self.connectionRef) == has(oldSelf.connectionRef)",message="connectionRef cannot be added or removed, recreate the policy"
 type PolicySpec struct {
 	// +kubebuilder:validation:Required
 	BucketName string `json:"bucketName"`
@@ -28,9 +33,52 @@ type PolicySpec struct {
 	// +kubebuilder:validation:Optional
 	SecretName string `json:"secretName"`
 
+	// CredentialRotation periodically regenerates the credentials of the Secret.
+	// +kubebuilder:validation:Optional
+	CredentialRotation *CredentialRotation `json:"credentialRotation,omitempty"`
+
+	// CredentialMode selects long-lived (Static, the default) or temporary (STS) credentials for the Secret.
+	// +kubebuilder:validation:Optional
+	CredentialMode CredentialMode `json:"credentialMode,omitempty"`
+
+	// STS configures the temporary credentials of the STS mode.
+	// +kubebuilder:validation:Optional
+	STS *STSCredentials `json:"sts,omitempty"`
+
+	// SecretTemplate renders extra keys into the Secret, e.g. the configuration of S3 clients.
+	// +kubebuilder:validation:Optional
+	SecretTemplate *SecretTemplate `json:"secretTemplate,omitempty"`
+
+	// ConnectionRef selects the MinIO server hosting the policy, defaults to the one of the bucket.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="connectionRef is immutable"
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+
 	// +kubebuilder:validation:MinItems=1
-	// +kubebuilder:validation:Required
-	Statements []Statement `json:"statements"`
+	// +kubebuilder:validation:Optional
+	Statements []Statement `json:"statements,omitempty"`
+
+	// Document is a complete IAM policy in JSON, sent to MinIO as is except for its ID
+	// which is set to the marker of the canned policies managed by the controller,
+	// a document carrying another ID is refused.
+	// Statements granting access outside of the bucket are refused unless
+	// the namespace is allowed to do so by the controller.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	Document string `json:"document,omitempty"`
+
+	// AccessKeys are additional keys sharing the permissions of the policy,
+	// each of them can be disabled, expired or rotated independently.
+	// Their Secrets are named after the policy and must be controlled by it.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	AccessKeys []AccessKey `json:"accessKeys,omitempty"`
+
+	// Identities grants the policy to users of the identity provider configured on MinIO
+	// instead of a generated user, no Secret is created for the policy. They are set on creation.
+	// +kubebuilder:validation:Optional
+	Identities *ExternalIdentities `json:"identities,omitempty"`
 }
 
 type Statement struct {
@@ -43,11 +91,166 @@ type Statement struct {
 	// +kubebuilder:validation:Required
 	// +listType=set
 	Actions []string `json:"actions"`
+	// Conditions restrict the requests the statement applies to.
+	// +kubebuilder:validation:Optional
+	Conditions *StatementConditions `json:"conditions,omitempty"`
+}
+
+// StatementConditions are translated into the Condition block of the statement,
+// all of them must match for the statement to apply.
+// +kubebuilder:validation:XValidation:rule="!has(self.notBefore) || !has(self.notAfter) || self.notBefore < self.notAfter",message="notBefore must be earlier than notAfter"
+type StatementConditions struct {
+	// SourceIPs only matches requests coming from these CIDRs (aws:SourceIp).
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	SourceIPs []string `json:"sourceIPs,omitempty"`
+	// NotSourceIPs excludes requests coming from these CIDRs (aws:SourceIp).
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	NotSourceIPs []string `json:"notSourceIPs,omitempty"`
+	// SecureTransport only matches requests sent over TLS when true, in clear text when false (aws:SecureTransport).
+	// +kubebuilder:validation:Optional
+	SecureTransport *bool `json:"secureTransport,omitempty"`
+	// NotBefore only matches requests made from this date (aws:CurrentTime).
+	// +kubebuilder:validation:Optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// NotAfter only matches requests made before this date (aws:CurrentTime).
+	// +kubebuilder:validation:Optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// Prefixes restricts the listings to these prefixes, wildcards are allowed (s3:prefix).
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	Prefixes []string `json:"prefixes,omitempty"`
+	// Custom holds any other condition supported by MinIO, e.g. on s3:delimiter or s3:max-keys.
+	// +kubebuilder:validation:Optional
+	Custom []Condition `json:"custom,omitempty"`
+}
+
+// Condition is a raw IAM condition: Operator applied on Key with the given Values.
+type Condition struct {
+	// Operator is an IAM condition operator, e.g. StringEquals or NumericLessThan.
+	// +kubebuilder:validation:Required
+	Operator string `json:"operator"`
+	// Key is a condition key, e.g. s3:delimiter.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Values []string `json:"values"`
+}
+
+// AccessKey is a service account of the policy user, its credentials are stored in a dedicated Secret.
+// Deleting the Secret regenerates the key.
+type AccessKey struct {
+	// Name identifies the key within the policy, it is also the name of the service account.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// SecretName holds the credentials of the key, defaults to "<policy>-<name>".
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Expiration revokes the key once reached.
+	// +kubebuilder:validation:Optional
+	Expiration *metav1.Time `json:"expiration,omitempty"`
+
+	// Disabled refuses the requests signed by the key, its credentials are kept.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// RotationInterval periodically replaces the key by a new one, e.g. "720h".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="rotationInterval must be positive"
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+}
+
+// SecretNameFor returns the name of the Secret of the key.
+func (k AccessKey) SecretNameFor(policy string) string {
+	if k.SecretName != "" {
+		return k.SecretName
+	}
+	return policy + "-" + k.Name
+}
+
+// AccessKeyState is the state of an access key in MinIO.
+// +kubebuilder:validation:Enum=Enabled;Disabled;Expired
+type AccessKeyState string
+
+const (
+	AccessKeyEnabled  AccessKeyState = "Enabled"
+	AccessKeyDisabled AccessKeyState = "Disabled"
+	AccessKeyExpired  AccessKeyState = "Expired"
+)
+
+// AccessKeyStatus reports an access key of the policy.
+type AccessKeyStatus struct {
+	Name string `json:"name"`
+
+	SecretName string `json:"secretName"`
+
+	// AccessKeyID is the access key currently stored in the Secret.
+	AccessKeyID string `json:"accessKeyID,omitempty"`
+
+	State AccessKeyState `json:"state,omitempty"`
+
+	// LastRotationTime is when the credentials of the key were last generated.
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+}
+
+// ExternalIdentities are the LDAP or OIDC identities the canned policy is granted to.
+// +kubebuilder:validation:XValidation:rule="has(self.ldapUsers) || has(self.ldapGroups) || (has(self.oidc) && self.oidc)",message="at least one identity must be bound"
+type ExternalIdentities struct {
+	// LDAPUsers are the distinguished names of the LDAP users the canned policy is attached to.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	LDAPUsers []string `json:"ldapUsers,omitempty"`
+
+	// LDAPGroups are the distinguished names of the LDAP groups the canned policy is attached to.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	LDAPGroups []string `json:"ldapGroups,omitempty"`
+
+	// OIDC grants the canned policy to the OIDC users whose policy claim, as configured on MinIO,
+	// lists its name. The name is reported in the status for the identity provider.
+	// +kubebuilder:validation:Optional
+	OIDC bool `json:"oidc,omitempty"`
+}
+
+// IdentityType is the kind of an external identity.
+// +kubebuilder:validation:Enum=LDAPUser;LDAPGroup;OIDCClaim
+type IdentityType string
+
+const (
+	IdentityLDAPUser  IdentityType = "LDAPUser"
+	IdentityLDAPGroup IdentityType = "LDAPGroup"
+	IdentityOIDCClaim IdentityType = "OIDCClaim"
+)
+
+// BoundIdentity is an external identity the canned policy is granted to.
+type BoundIdentity struct {
+	Type IdentityType `json:"type"`
+
+	// Name is the distinguished name of the LDAP user or group, or the value
+	// the policy claim of the OIDC tokens must hold.
+	Name string `json:"name"`
 }
 
 // PolicyStatus defines the observed state of Policy.
 type PolicyStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// Credentials records the rotations of the credentials of the Secret.
+	Credentials *CredentialsStatus `json:"credentials,omitempty"`
+
+	// AccessKeys reports the keys of spec.accessKeys.
+	// +listType=map
+	// +listMapKey=name
+	AccessKeys []AccessKeyStatus `json:"accessKeys,omitempty"`
+
+	// Identities are the external identities the canned policy is granted to.
+	Identities []BoundIdentity `json:"identities,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -24,6 +24,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -35,8 +37,10 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -48,6 +52,7 @@ import (
 	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
 	"github.com/IxDay/internal/controller"
 	"github.com/IxDay/internal/minio"
+	webhookminiov1alpha1 "github.com/IxDay/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -75,6 +80,13 @@ func main() {
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	var connectionSecret string
+	var bucketNameTemplate, clusterID string
+	var unrestrictedNamespaces string
+	var minioTLS, minioInsecureSkipVerify bool
+	var minioCAFile, minioCertFile, minioKeyFile string
+	var bucketUsageMetrics bool
+	var resyncInterval, gcInterval time.Duration
+	var gcMode string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -94,6 +106,31 @@ func main() {
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	flag.StringVar(&connectionSecret, "connection-secret", "minio-controller-secret",
 		"name of a secret containing connections strings to a minio cluster")
+	flag.StringVar(&bucketNameTemplate, "bucket-name-template", minio.DefaultNamingTemplate,
+		"Go template computing the name of new buckets, "+
+			"{{ .Namespace }}, {{ .Name }} and {{ .ClusterID }} are available. "+
+			"Names longer than 63 characters are truncated and suffixed with a hash.")
+	flag.StringVar(&clusterID, "cluster-id", "", "identifier of the cluster, available in the bucket name template")
+	flag.StringVar(&unrestrictedNamespaces, "unrestricted-namespaces", "",
+		"comma separated list of namespaces whose policy documents may grant access outside of their bucket")
+	flag.BoolVar(&minioTLS, "minio-tls", false,
+		"connect to the minio cluster over HTTPS, also enabled by an https:// endpoint in the connection secret")
+	flag.StringVar(&minioCAFile, "minio-ca-file", "",
+		"PEM bundle verifying the minio certificate, overridden by ca.crt in the connection secret")
+	flag.StringVar(&minioCertFile, "minio-cert-file", "",
+		"client certificate presented to minio for mutual TLS, overridden by tls.crt in the connection secret")
+	flag.StringVar(&minioKeyFile, "minio-key-file", "",
+		"key of the client certificate, overridden by tls.key in the connection secret")
+	flag.BoolVar(&minioInsecureSkipVerify, "minio-insecure-skip-verify", false,
+		"skip the verification of the minio certificate, for test environments only")
+	flag.DurationVar(&resyncInterval, "resync-interval", 10*time.Minute,
+		"interval between two verifications of the minio state of the available buckets and policies, 0 disables it")
+	flag.StringVar(&gcMode, "gc-mode", string(controller.GCModeReport),
+		"what to do with the buckets, policies and users left in minio without resource: "+
+			"disabled, report through metrics and events, or delete")
+	flag.DurationVar(&gcInterval, "gc-interval", time.Hour, "interval between two collections of the minio orphans")
+	flag.BoolVar(&bucketUsageMetrics, "bucket-usage-metrics", false,
+		"report the size and the number of objects of every bucket, queried from minio at each scrape")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -231,35 +268,151 @@ func main() {
 		}
 		namespace = string(b)
 	}
+	// without the connection secret, only the resources referencing a connection are managed
+	// until the secret is created, it is reloaded by the credentials controller on changes
+	minioTLSOptions := minio.ClientOptions{Secure: minioTLS, InsecureSkipVerify: minioInsecureSkipVerify}
+	for _, file := range []struct {
+		path string
+		data *[]byte
+	}{
+		{minioCAFile, &minioTLSOptions.CA},
+		{minioCertFile, &minioTLSOptions.Certificate},
+		{minioKeyFile, &minioTLSOptions.Key},
+	} {
+		if file.path == "" {
+			continue
+		}
+		if *file.data, err = os.ReadFile(file.path); err != nil {
+			setupLog.Error(err, "failed to read minio TLS file", "file", file.path)
+			os.Exit(1)
+		}
+	}
+	clients := minio.NewRegistry(nil)
 	secret, err := generatedClient.CoreV1().Secrets(namespace).
 		Get(context.Background(), connectionSecret, metav1.GetOptions{})
 	if err != nil {
 		setupLog.Error(err, "failed to retrieve connection strings secret")
+	} else if options, err := minio.OptionsFromSecret(secret); err != nil {
+		setupLog.Error(err, "invalid connection strings secret")
+	} else if _, err := clients.SetDefault(options.WithTLS(minioTLSOptions)); err != nil {
+		setupLog.Error(err, "failed to instantiate minio client from secret")
 	}
 
-	client, err := minio.NewClientFromSecret(secret)
+	naming, err := minio.NewNamingStrategy(bucketNameTemplate, clusterID)
 	if err != nil {
-		setupLog.Error(err, "failed to instantiate minio client from secret")
+		setupLog.Error(err, "invalid bucket name template")
+		os.Exit(1)
 	}
 
+	bucketRetry := controller.NewAuthRetry(func() client.Object { return &miniov1alpha1.Bucket{} })
+	policyRetry := controller.NewAuthRetry(func() client.Object { return &miniov1alpha1.Policy{} })
 	if err = (&controller.BucketReconciler{
-		Client:      mgr.GetClient(),
-		Scheme:      mgr.GetScheme(),
-		MinioClient: client,
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor("bucket-controller"),
+		Clients:        clients,
+		Naming:         naming,
+		AuthRetry:      bucketRetry,
+		ResyncInterval: resyncInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Minio")
 		os.Exit(1)
 	}
 	if err = (&controller.PolicyReconciler{
-		Client:      mgr.GetClient(),
-		Scheme:      mgr.GetScheme(),
-		MinioClient: client,
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		Recorder:               mgr.GetEventRecorderFor("policy-controller"),
+		Clients:                clients,
+		AuthRetry:              policyRetry,
+		UnrestrictedNamespaces: splitList(unrestrictedNamespaces),
+		ResyncInterval:         resyncInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Policy")
 		os.Exit(1)
 	}
+	if err = (&controller.GroupReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor("group-controller"),
+		Clients:        clients,
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Group")
+		os.Exit(1)
+	}
+	if err = (&controller.CredentialsReconciler{
+		Client:     mgr.GetClient(),
+		Clients:    clients,
+		SecretName: connectionSecret,
+		Namespace:  namespace,
+		TLS:        minioTLSOptions,
+		Retries:    []*controller.AuthRetry{bucketRetry, policyRetry},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Credentials")
+		os.Exit(1)
+	}
+	if err = (&controller.MinioConnectionReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Clients: clients,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MinioConnection")
+		os.Exit(1)
+	}
+	if err = (&controller.ClusterMinioConnectionReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Clients:   clients,
+		Namespace: namespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterMinioConnection")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookminiov1alpha1.SetupBucketWebhookWithManager(mgr, naming); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Bucket")
+			os.Exit(1)
+		}
+		if err = webhookminiov1alpha1.SetupPolicyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Policy")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	mode, err := controller.ParseGCMode(gcMode)
+	if err != nil {
+		setupLog.Error(err, "invalid gc mode")
+		os.Exit(1)
+	}
+	if mode != controller.GCModeDisabled && gcInterval <= 0 {
+		setupLog.Error(nil, "gc interval must be positive", "gc-interval", gcInterval)
+		os.Exit(1)
+	}
+	if mode != controller.GCModeDisabled {
+		if err := mgr.Add(&controller.OrphanCollector{
+			Reader:     mgr.GetAPIReader(),
+			Recorder:   mgr.GetEventRecorderFor("orphan-collector"),
+			Clients:    clients,
+			Mode:       mode,
+			Interval:   gcInterval,
+			SecretName: connectionSecret,
+			Namespace:  namespace,
+		}); err != nil {
+			setupLog.Error(err, "unable to add orphan collector to manager")
+			os.Exit(1)
+		}
+	}
+
+	metrics.Registry.MustRegister(minio.Collectors()...)
+	metrics.Registry.MustRegister(controller.Collectors()...)
+	metrics.Registry.MustRegister(&controller.ResourceCollector{
+		Reader:      mgr.GetClient(),
+		Clients:     clients,
+		BucketUsage: bucketUsageMetrics,
+	})
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -291,3 +444,14 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// splitList parses a comma separated flag, ignoring blank entries.
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+// errSecretNotControlled is returned when the secret of an access key exists without being
+// controlled by the policy, it is never taken over.
+var errSecretNotControlled = errors.New("secret is not controlled by the policy")
+
+// reconcileAccessKeys converges the service accounts of spec.accessKeys under the user of the policy,
+// the keys dropped from the spec are revoked along with their secret. The status is updated in place
+// and left to the caller to persist, except after a rotation which is recorded right away.
+// MinIO removes the service accounts along with their user and can not move them to another one.
+// When an overlapping rotation replaces the user, the keys are recreated here under the new user with
+// the same credentials, they are refused in between, until the next reconciliation if this one fails.
+func (r *PolicyReconciler) reconcileAccessKeys(
+	ctx context.Context, minioClient minio.Client, policy *miniov1alpha1.Policy,
+	bucket, parent string, drift *driftReport, now time.Time,
+) error {
+	log := log.FromContext(ctx)
+
+	statuses := make([]miniov1alpha1.AccessKeyStatus, 0, len(policy.Spec.AccessKeys))
+	for _, key := range policy.Spec.AccessKeys {
+		status := findAccessKeyStatus(policy.Status.AccessKeys, key.Name)
+		secretName := key.SecretNameFor(policy.Name)
+		if status.SecretName != "" && status.SecretName != secretName {
+			if err := r.deleteAccessKeySecret(ctx, policy, status.SecretName); err != nil {
+				log.Error(err, "Failed to delete the previous access key Secret", "Secret.Name", status.SecretName)
+				return err
+			}
+		}
+		status.Name, status.SecretName = key.Name, secretName
+
+		secret := &corev1.Secret{}
+		rotated := false
+		err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: policy.Namespace}, secret)
+		if apierrors.IsNotFound(err) {
+			if secret, err = r.secretForAccessKey(policy, secretName); err != nil {
+				log.Error(err, "Failed to define new secret resource for access key", "AccessKey.Name", key.Name)
+				return err
+			}
+			log.Info("Creating a new access key Secret", "Secret.Name", secret.Name)
+			if err := r.Create(ctx, secret); err != nil {
+				log.Error(err, "Failed to create new Secret", "Secret.Name", secret.Name)
+				return err
+			}
+			r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventSecretCreated,
+				"Generated access key %s in secret %s", key.Name, secret.Name)
+			rotated = true
+		} else if err != nil {
+			log.Error(err, "Failed to get access key Secret", "Secret.Name", secretName)
+			return err
+		} else if !metav1.IsControlledBy(secret, policy) {
+			return fmt.Errorf("%w: %s", errSecretNotControlled, secretName)
+		} else if accessKeyRotationDue(key, status, now) {
+			if err := generateAccessKey(secret); err != nil {
+				log.Error(err, "Failed to generate new credentials", "Secret.Name", secret.Name)
+				return err
+			}
+			log.Info("Rotating access key", "Secret.Name", secret.Name)
+			if err := r.Update(ctx, secret); err != nil {
+				log.Error(err, "Failed to update Secret with the new credentials", "Secret.Name", secret.Name)
+				return err
+			}
+			r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventCredentialsRotated,
+				"Rotated access key %s in secret %s", key.Name, secret.Name)
+			rotated = true
+		}
+		if rotated {
+			last := metav1.NewTime(now)
+			status.LastRotationTime = &last
+			// recorded right away, a failure below must not trigger another rotation,
+			// the previous access key is still reported to be revoked afterwards
+			setAccessKeyStatus(&policy.Status.AccessKeys, status)
+			if err := r.Status().Update(ctx, policy); err != nil {
+				log.Error(err, "Failed to update Policy status")
+				return err
+			}
+		} else if status.LastRotationTime == nil {
+			// generated before the status was recorded, they are as old as the secret
+			created := secret.CreationTimestamp
+			status.LastRotationTime = &created
+		}
+
+		accessKey := string(secret.Data["user"])
+		if status.AccessKeyID != "" && status.AccessKeyID != accessKey {
+			log.Info("Revoking the replaced access key", "AccessKey.Name", key.Name)
+			if err := minioClient.ServiceAccountDelete(ctx, status.AccessKeyID, parent); err != nil {
+				log.Error(err, "Failed to delete the replaced access key", "AccessKey.Name", key.Name)
+				return err
+			}
+		}
+		status.AccessKeyID = accessKey
+
+		if key.Expiration != nil && !now.Before(key.Expiration.Time) {
+			// MinIO disables the expired account on its own, removing it keeps the user clean
+			if err := minioClient.ServiceAccountDelete(ctx, accessKey, parent); err != nil {
+				log.Error(err, "Failed to delete the expired access key", "AccessKey.Name", key.Name)
+				return err
+			}
+			if status.State != miniov1alpha1.AccessKeyExpired {
+				r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventAccessKeyExpired,
+					"Access key %s expired", key.Name)
+			}
+			status.State = miniov1alpha1.AccessKeyExpired
+		} else {
+			account := minio.ServiceAccount{
+				Parent: parent, AccessKey: accessKey, SecretKey: string(secret.Data["password"]),
+				Name: key.Name, Disabled: key.Disabled,
+			}
+			if key.Expiration != nil {
+				account.Expiration = &key.Expiration.Time
+			}
+			changed, err := minioClient.ServiceAccountReconcile(ctx, account)
+			if err != nil {
+				log.Error(err, "Failed to reconcile access key", "AccessKey.Name", key.Name)
+				return err
+			}
+			drift.add(changed && !rotated, "access key "+key.Name)
+			status.State = miniov1alpha1.AccessKeyEnabled
+			if key.Disabled {
+				status.State = miniov1alpha1.AccessKeyDisabled
+			}
+		}
+
+		if changed, err := renderSecret(policy.Spec.SecretTemplate,
+			secretData(minioClient, bucket, secret), secret); err != nil {
+			// the template is validated on the policy secret first
+			log.Error(err, "Failed to render secret template", "Secret.Name", secret.Name)
+			return err
+		} else if changed {
+			if err := r.Update(ctx, secret); err != nil {
+				log.Error(err, "Failed to update Secret", "Secret.Name", secret.Name)
+				return err
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, status := range policy.Status.AccessKeys {
+		if slices.ContainsFunc(policy.Spec.AccessKeys, func(key miniov1alpha1.AccessKey) bool {
+			return key.Name == status.Name
+		}) {
+			continue
+		}
+		log.Info("Revoking access key", "AccessKey.Name", status.Name)
+		if status.AccessKeyID != "" {
+			if err := minioClient.ServiceAccountDelete(ctx, status.AccessKeyID, parent); err != nil {
+				log.Error(err, "Failed to delete access key", "AccessKey.Name", status.Name)
+				return err
+			}
+		}
+		if err := r.deleteAccessKeySecret(ctx, policy, status.SecretName); err != nil {
+			log.Error(err, "Failed to delete access key Secret", "Secret.Name", status.SecretName)
+			return err
+		}
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventAccessKeyRevoked,
+			"Revoked access key %s", status.Name)
+	}
+	policy.Status.AccessKeys = statuses
+	if len(statuses) == 0 {
+		policy.Status.AccessKeys = nil
+	}
+	return nil
+}
+
+func (r *PolicyReconciler) secretForAccessKey(policy *miniov1alpha1.Policy, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   policy.Namespace,
+			Annotations: map[string]string{annotationPolicy: policy.Name},
+		},
+	}
+	if err := generateAccessKey(secret); err != nil {
+		return nil, err
+	}
+	if err := ctrl.SetControllerReference(policy, secret, r.Scheme); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// deleteAccessKeySecret removes the secret of an access key, a secret not owned by the policy is kept.
+func (r *PolicyReconciler) deleteAccessKeySecret(ctx context.Context, policy *miniov1alpha1.Policy, name string) error {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: policy.Namespace}, secret)
+	if apierrors.IsNotFound(err) || (err == nil && !metav1.IsControlledBy(secret, policy)) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, secret))
+}
+
+// generateAccessKey stores new credentials in the secret, the access key is regenerated so
+// the previous one can be revoked independently.
+func generateAccessKey(secret *corev1.Secret) error {
+	user, err := minio.GenerateAccessKey(0, nil)
+	if err != nil {
+		return err
+	}
+	password, err := minio.GenerateSecretKey(0, nil)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data["user"], secret.Data["password"] = user, password
+	return nil
+}
+
+// accessKeyRotationDue reports whether the rotation interval of the key elapsed.
+func accessKeyRotationDue(key miniov1alpha1.AccessKey, status miniov1alpha1.AccessKeyStatus, now time.Time) bool {
+	return key.RotationInterval != nil && status.LastRotationTime != nil &&
+		!now.Before(status.LastRotationTime.Add(key.RotationInterval.Duration))
+}
+
+// requeueForAccessKeys shortens the result so the next rotation or expiration of a key is not missed.
+func requeueForAccessKeys(
+	result *ctrl.Result, keys []miniov1alpha1.AccessKey, statuses []miniov1alpha1.AccessKeyStatus, now time.Time,
+) {
+	for _, key := range keys {
+		status := findAccessKeyStatus(statuses, key.Name)
+		if key.RotationInterval != nil && status.LastRotationTime != nil {
+			requeueBefore(result, status.LastRotationTime.Add(key.RotationInterval.Duration), now)
+		}
+		if key.Expiration != nil && now.Before(key.Expiration.Time) {
+			requeueBefore(result, key.Expiration.Time, now)
+		}
+	}
+}
+
+// findAccessKeyStatus returns the status of the key, an empty one when it is not reported yet.
+func findAccessKeyStatus(statuses []miniov1alpha1.AccessKeyStatus, name string) miniov1alpha1.AccessKeyStatus {
+	for _, status := range statuses {
+		if status.Name == name {
+			return status
+		}
+	}
+	return miniov1alpha1.AccessKeyStatus{}
+}
+
+func setAccessKeyStatus(statuses *[]miniov1alpha1.AccessKeyStatus, status miniov1alpha1.AccessKeyStatus) {
+	for i := range *statuses {
+		if (*statuses)[i].Name == status.Name {
+			(*statuses)[i] = status
+			return
+		}
+	}
+	*statuses = append(*statuses, status)
+}
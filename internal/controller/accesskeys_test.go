@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+)
+
+var _ = Describe("Access keys", func() {
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Now()
+	})
+
+	It("should rotate a key once its interval elapsed", func() {
+		key := miniov1alpha1.AccessKey{Name: "ci", RotationInterval: &metav1.Duration{Duration: time.Hour}}
+		last := metav1.NewTime(now.Add(-30 * time.Minute))
+		status := miniov1alpha1.AccessKeyStatus{Name: "ci", LastRotationTime: &last}
+		Expect(accessKeyRotationDue(key, status, now)).To(BeFalse())
+		Expect(accessKeyRotationDue(key, status, now.Add(time.Hour))).To(BeTrue())
+
+		key.RotationInterval = nil
+		Expect(accessKeyRotationDue(key, status, now.Add(time.Hour))).To(BeFalse())
+	})
+
+	It("should requeue before the next rotation or expiration", func() {
+		last := metav1.NewTime(now)
+		expiration := metav1.NewTime(now.Add(10 * time.Minute))
+		keys := []miniov1alpha1.AccessKey{
+			{Name: "ci", RotationInterval: &metav1.Duration{Duration: time.Hour}},
+			{Name: "backup", Expiration: &expiration},
+		}
+		statuses := []miniov1alpha1.AccessKeyStatus{{Name: "ci", LastRotationTime: &last}}
+
+		result := ctrl.Result{}
+		requeueForAccessKeys(&result, keys, statuses, now)
+		Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+
+		result = ctrl.Result{}
+		requeueForAccessKeys(&result, keys, statuses, now.Add(20*time.Minute))
+		Expect(result.RequeueAfter).To(Equal(40 * time.Minute))
+	})
+
+	It("should name the secret after the policy by default", func() {
+		Expect(miniov1alpha1.AccessKey{Name: "ci"}.SecretNameFor("reader")).To(Equal("reader-ci"))
+		Expect(miniov1alpha1.AccessKey{Name: "ci", SecretName: "ci-keys"}.SecretNameFor("reader")).To(Equal("ci-keys"))
+	})
+})
@@ -19,17 +19,20 @@ package controller
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -46,6 +49,22 @@ import (
 const (
 	// typeAvailableBucket represents the status of the Bucket reconciliation
 	typeAvailableBucket = "Available"
+	// reasonInvalidBucketName is set when no valid bucket name can be computed for the resource
+	reasonInvalidBucketName = "InvalidBucketName"
+	// typeQuotaWarningBucket is raised when the bucket usage crosses the warning percentage of its quota
+	typeQuotaWarningBucket = "QuotaWarning"
+	// typeObjectLockBucket represents the status of the object locking on the bucket
+	typeObjectLockBucket = "ObjectLock"
+	// typeEncryptedBucket represents the status of the default encryption of the bucket
+	typeEncryptedBucket = "Encrypted"
+	// typeDeletingBucket represents the progress of the deletion of the bucket
+	typeDeletingBucket = "Deleting"
+	// typeDeletionBlockedBucket is raised when the bucket still holds objects it cannot remove
+	typeDeletionBlockedBucket = "DeletionBlocked"
+	// maximum number of object versions removed by a single reconciliation when force deleting
+	purgeBatchSize = 1000
+	// usage is computed asynchronously by MinIO, buckets with a quota are refreshed periodically
+	quotaRefreshInterval = 5 * time.Minute
 	// name of our custom finalizer
 	finalizerName    = "bucket.ixday.github.io/finalizer"
 	annotationBucket = "bucket.ixday.github.io/secret"
@@ -54,8 +73,14 @@ const (
 // BucketReconciler reconciles a Bucket object
 type BucketReconciler struct {
 	client.Client
-	Scheme      *runtime.Scheme
-	MinioClient minio.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Clients  *minio.Registry
+	Naming   minio.NamingStrategy
+	// AuthRetry, when set, retries the buckets refused by MinIO once the credentials are reloaded
+	AuthRetry *AuthRetry
+	// ResyncInterval, when set, periodically verifies the MinIO state of the available buckets
+	ResyncInterval time.Duration
 }
 
 type Bucket = miniov1alpha1.Bucket
@@ -64,6 +89,7 @@ type Bucket = miniov1alpha1.Bucket
 // +kubebuilder:rbac:groups=minio.ixday.github.io,resources=buckets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=minio.ixday.github.io,resources=buckets/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -91,6 +117,28 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	minioClient, err := r.Clients.Client(bucket.Spec.ConnectionRef, bucket.Namespace)
+	if err != nil {
+		// connections are reconciled on their own, wait for the client to be registered
+		// unless the connection was deleted, the resource could never be released otherwise
+		if released, err := releaseWithoutConnection(ctx, r.Client, r.Recorder, bucket, bucket.Spec.ConnectionRef, finalizerName); err != nil {
+			return ctrl.Result{}, err
+		} else if released {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "MinIO connection unavailable")
+		if bucket.DeletionTimestamp.IsZero() {
+			meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeAvailableBucket,
+				Status: metav1.ConditionFalse, Reason: reasonConnectionNotReady,
+				Message: fmt.Sprintf("MinIO connection unavailable: %s", err)})
+			if err := r.Status().Update(ctx, bucket); err != nil {
+				log.Error(err, "Failed to update bucket status")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: connectionRetryInterval}, nil
+	}
+
 	// https://book.kubebuilder.io/reference/using-finalizers
 	// examine DeletionTimestamp to determine if object is under deletion
 	if bucket.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -106,19 +154,10 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	} else {
 		// The object is being deleted
 		if controllerutil.ContainsFinalizer(bucket, finalizerName) {
-			// our finalizer is present, so lets handle any external dependency
-			log.Info("Deleting associated users and policies", "Bucket.Name", bucket.BucketName())
-			if err := r.MinioClient.PolicyDelete(ctx, bucket.BucketName()); err != nil {
-				log.Error(err, "Failed deleting associated users and policies", "Bucket.Name", bucket.BucketName())
-				return ctrl.Result{}, err
-			}
-
-			log.Info("Deleting Bucket", "Bucket.Name", bucket.BucketName())
-			if err := r.MinioClient.BucketDelete(ctx, bucket.BucketName()); err != nil {
-				// if fail to delete the external dependency here, return with error
-				// so that it can be retried.
-				log.Error(err, "Failed deleting bucket", "Bucket.Name", bucket.BucketName())
-				return ctrl.Result{}, err
+			// our finalizer is present, so lets handle any external dependency,
+			// a non zero result means the deletion is still in progress.
+			if result, err := r.deleteExternalResources(ctx, minioClient, bucket); err != nil || !result.IsZero() {
+				return result, err
 			}
 
 			// remove our finalizer from the list and update it.
@@ -132,13 +171,31 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, nil
 	}
 
-	// Let's just set the status as Unknown when no status is available
-	if len(bucket.Status.Conditions) == 0 {
-		condition := metav1.Condition{
-			Type: typeAvailableBucket, Status: metav1.ConditionUnknown,
-			Reason: "Reconciling", Message: "Starting reconciliation",
+	// Let's just set the status as Unknown when no status is available,
+	// and record the name of the bucket in MinIO once and for all
+	if len(bucket.Status.Conditions) == 0 || bucket.Status.BucketName == "" {
+		name, err := r.resolveBucketName(bucket)
+		if err != nil {
+			log.Error(err, "Invalid bucket name")
+			r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventInvalidBucketName,
+				"Failed to compute a valid bucket name: %s", err)
+			meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeAvailableBucket,
+				Status: metav1.ConditionFalse, Reason: reasonInvalidBucketName,
+				Message: fmt.Sprintf("Failed to compute a valid bucket name: %s", err)})
+			if err := r.Status().Update(ctx, bucket); err != nil {
+				log.Error(err, "Failed to update bucket status")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+		bucket.Status.BucketName = name
+		if len(bucket.Status.Conditions) == 0 {
+			condition := metav1.Condition{
+				Type: typeAvailableBucket, Status: metav1.ConditionUnknown,
+				Reason: "Reconciling", Message: "Starting reconciliation",
+			}
+			meta.SetStatusCondition(&bucket.Status.Conditions, condition)
 		}
-		meta.SetStatusCondition(&bucket.Status.Conditions, condition)
 		if err := r.Status().Update(ctx, bucket); err != nil {
 			log.Error(err, "Failed to update bucket status")
 			return ctrl.Result{}, err
@@ -155,33 +212,165 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 	}
 
-	found, err := r.MinioClient.BucketExists(ctx, bucket.BucketName())
+	drift := newDriftReport(bucket.Status.Conditions, typeAvailableBucket, bucket.Generation)
+	found, err := minioClient.BucketExists(ctx, bucket.BucketName())
 	if err != nil {
 		log.Error(err, "Failed to check bucket exists")
 	} else if !found {
 		log.Info("Creating a new Bucket", "Bucket.Name", bucket.BucketName())
 
-		if err := r.MinioClient.BucketCreate(ctx, bucket.BucketName()); err != nil {
+		objectLocking := bucket.Spec.ObjectLock != nil
+		if err := minioClient.BucketCreate(ctx, bucket.BucketName(), objectLocking); err != nil {
 			log.Error(err, "Failed to create new Bucket",
 				"Bucket.Name", bucket.BucketName())
 			return ctrl.Result{}, err
 		}
+		if _, err := minioClient.BucketClaim(ctx, bucket.BucketName(), bucket.Owner(), true); err != nil {
+			log.Error(err, "Failed to claim new Bucket",
+				"Bucket.Name", bucket.BucketName())
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventBucketCreated, "Created bucket %s", bucket.BucketName())
+		// the bucket was removed behind our back, record it before the requeue
+		drift.add(true, "bucket")
+		if drift.tracking {
+			r.recordDrift(bucket, drift)
+			if err := r.Status().Update(ctx, bucket); err != nil {
+				log.Error(err, "Failed to update Bucket status")
+				return ctrl.Result{}, err
+			}
+		}
 		// Bucket created successfully
 		// We will requeue the reconciliation so that we can ensure the state
 		// and move forward for the next operations
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
-	if changed, err := r.MinioClient.BucketPolicyReconcile(ctx, bucket.BucketName(), bucket.Spec.Policy); err != nil {
+
+	// buckets named after the resource are ours by convention, any other one must be adopted explicitly
+	adopt := bucket.Spec.Adopt || bucket.Spec.ExternalName == ""
+	claimed, err := minioClient.BucketClaim(ctx, bucket.BucketName(), bucket.Owner(), adopt)
+	if errors.Is(err, minio.ErrBucketClaimed) || errors.Is(err, minio.ErrBucketNotAdopted) {
+		log.Info("Refusing to manage existing Bucket", "Bucket.Name", bucket.BucketName(), "reason", err.Error())
+		r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventAdoptionRefused,
+			"Bucket %s can not be managed: %s", bucket.BucketName(), err)
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeAvailableBucket,
+			Status: metav1.ConditionFalse, Reason: "AdoptionRefused",
+			Message: fmt.Sprintf("Bucket %s can not be managed: %s", bucket.BucketName(), err)})
+		if err := r.Status().Update(ctx, bucket); err != nil {
+			log.Error(err, "Failed to update Bucket status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to claim Bucket")
+		return ctrl.Result{}, err
+	} else if claimed {
+		log.Info("Adopted existing Bucket", "Bucket.Name", bucket.BucketName())
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventBucketAdopted, "Adopted bucket %s", bucket.BucketName())
+	}
+
+	// the anonymous policy of adopted buckets is only changed when explicitly requested
+	anonymousPolicy := bucket.Spec.Policy
+	if anonymousPolicy == "" && bucket.Spec.ExternalName == "" {
+		anonymousPolicy = miniov1alpha1.PolicyPrivate
+	}
+	if anonymousPolicy == "" {
+		log.V(2).Info("Leaving bucket policy untouched")
+	} else if changed, err := minioClient.BucketPolicyReconcile(
+		ctx, bucket.BucketName(), anonymousPolicy,
+	); err != nil {
 		log.Error(err, "Failed to reconcile Bucket Policy")
 		return ctrl.Result{}, err
 	} else if changed {
+		drift.add(true, "anonymous policy")
 		log.Info("Reconciled bucket policy")
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventBucketPolicyUpdated,
+			"Set the anonymous policy to %s", anonymousPolicy)
+	}
+	changed, err := minioClient.BucketObjectLockReconcile(ctx, bucket.BucketName(), bucket.Spec.ObjectLock)
+	switch {
+	case errors.Is(err, minio.ErrObjectLockNotEnabled):
+		// object lock is immutable, retrying will not help so we only report it
+		log.Info("Refusing to enable object lock", "reason", err.Error())
+		r.Recorder.Event(bucket, corev1.EventTypeWarning, eventObjectLockImmutable, err.Error())
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeObjectLockBucket,
+			Status: metav1.ConditionFalse, Reason: "ObjectLockImmutable", Message: err.Error()})
+	case errors.Is(err, minio.ErrObjectLockUnmanaged):
+		// an omitted object lock is not managed, the one in place is reported so it is not overlooked
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeObjectLockBucket,
+			Status: metav1.ConditionTrue, Reason: "ObjectLockUnmanaged", Message: err.Error()})
+	case err != nil:
+		log.Error(err, "Failed to reconcile Bucket object lock")
+		return ctrl.Result{}, err
+	case bucket.Spec.ObjectLock != nil:
+		if changed {
+			drift.add(true, "object lock retention")
+			log.Info("Reconciled bucket object lock retention")
+			r.Recorder.Event(bucket, corev1.EventTypeNormal, eventObjectLockUpdated, "Updated the default retention")
+		}
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeObjectLockBucket,
+			Status: metav1.ConditionTrue, Reason: "ObjectLockEnabled", Message: "Object lock is enabled on the bucket"})
+	default:
+		meta.RemoveStatusCondition(&bucket.Status.Conditions, typeObjectLockBucket)
+	}
+	versioning, changed, err := minioClient.BucketVersioningReconcile(
+		ctx, bucket.BucketName(), bucket.Spec.Versioning,
+	)
+	if err != nil {
+		log.Error(err, "Failed to reconcile Bucket versioning")
+		return ctrl.Result{}, err
+	} else if changed {
+		drift.add(true, "versioning")
+		log.Info("Reconciled bucket versioning", "Versioning.Status", versioning)
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventVersioningUpdated, "Versioning is %s", versioning)
+	}
+	bucket.Status.Versioning = versioning
+	if changed, err := minioClient.BucketLifecycleReconcile(
+		ctx, bucket.BucketName(), bucket.Spec.Lifecycle,
+	); err != nil {
+		log.Error(err, "Failed to reconcile Bucket lifecycle")
+		return ctrl.Result{}, err
+	} else if changed {
+		drift.add(true, "lifecycle")
+		log.Info("Reconciled bucket lifecycle")
+		r.Recorder.Event(bucket, corev1.EventTypeNormal, eventLifecycleUpdated, "Updated the lifecycle rules")
+	}
+	changed, err = minioClient.BucketEncryptionReconcile(ctx, bucket.BucketName(), bucket.Spec.Encryption)
+	switch {
+	case errors.Is(err, minio.ErrKMSNotConfigured):
+		log.Info("Unable to encrypt bucket", "reason", err.Error())
+		r.Recorder.Event(bucket, corev1.EventTypeWarning, eventKMSNotConfigured, err.Error())
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeEncryptedBucket,
+			Status: metav1.ConditionFalse, Reason: "KMSNotConfigured", Message: err.Error()})
+	case err != nil:
+		log.Error(err, "Failed to reconcile Bucket encryption")
+		return ctrl.Result{}, err
+	case bucket.Spec.Encryption != nil:
+		if changed {
+			drift.add(true, "encryption")
+			log.Info("Reconciled bucket encryption")
+			r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventEncryptionUpdated,
+				"Objects are encrypted with %s", bucket.Spec.Encryption.Algorithm)
+		}
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeEncryptedBucket,
+			Status: metav1.ConditionTrue, Reason: "EncryptionEnabled",
+			Message: fmt.Sprintf("Objects are encrypted with %s", bucket.Spec.Encryption.Algorithm)})
+	default:
+		meta.RemoveStatusCondition(&bucket.Status.Conditions, typeEncryptedBucket)
+	}
+	result := ctrl.Result{}
+	if err := r.reconcileQuota(ctx, minioClient, bucket); err != nil {
+		log.Error(err, "Failed to reconcile Bucket quota")
+		return ctrl.Result{}, err
+	} else if bucket.Spec.Quota != nil {
+		result.RequeueAfter = quotaRefreshInterval
 	}
 
 	// if no secret provided we stop reconciliation, we do not want default policy
 	if bucket.Spec.SecretName == "" {
+		r.recordDrift(bucket, drift)
 		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeAvailableBucket,
-			Status: metav1.ConditionTrue, Reason: "Reconciling",
+			Status: metav1.ConditionTrue, Reason: "Reconciling", ObservedGeneration: bucket.Generation,
 			Message: fmt.Sprintf("Bucket %s created successfully", bucket.Name)})
 
 		if err := r.Status().Update(ctx, bucket); err != nil {
@@ -189,7 +378,8 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			return ctrl.Result{}, err
 		}
 
-		return ctrl.Result{}, nil
+		requeueForResync(&result, r.ResyncInterval)
+		return result, nil
 	}
 
 	secret, err := r.getSecret(ctx, bucket)
@@ -215,6 +405,7 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 				"Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
 			return ctrl.Result{}, err
 		}
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventSecretCreated, "Generated credentials in secret %s", secret.Name)
 		// Secret created successfully
 		// We will requeue the reconciliation so that we can ensure the state
 		// and move forward for the next operations
@@ -235,21 +426,136 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 	}
 
+	if changed, err := switchCredentialMode(bucket.Spec.CredentialMode, secret); err != nil {
+		log.Error(err, "Failed to switch the credential mode", "Secret.Name", secret.Name)
+		return ctrl.Result{}, err
+	} else if changed {
+		log.Info("Switching the credential mode", "Secret.Name", secret.Name, "Mode", bucket.Spec.CredentialMode)
+		if err := r.Update(ctx, secret); err != nil {
+			log.Error(err, "Failed to update Secret", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	now := time.Now()
+	if rotated, err := rotateStaticCredentials(bucket.Spec.CredentialMode, bucket.Annotations,
+		bucket.Spec.CredentialRotation, &bucket.Status.Credentials, secret, now); err != nil {
+		log.Error(err, "Failed to generate new credentials", "Secret.Name", secret.Name)
+		return ctrl.Result{}, err
+	} else if rotated {
+		log.Info("Rotating credentials", "Secret.Name", secret.Name)
+		if err := r.Update(ctx, secret); err != nil {
+			log.Error(err, "Failed to update Secret with the new credentials", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventCredentialsRotated,
+			"Rotated the credentials of secret %s", secret.Name)
+		// the user replaced by the rotation is not a drift
+		drift.tracking = false
+		// recorded right away, a failure below must not trigger another rotation
+		if err := r.Status().Update(ctx, bucket); err != nil {
+			log.Error(err, "Failed to update Bucket status")
+			return ctrl.Result{}, err
+		}
+	}
+
 	log.V(2).Info("Reconciling bucket policy")
 	policy := minio.NewDefaultPolicy(bucket.BucketName())
-	if err := policy.SetUser(secret.Data["user"], secret.Data["password"]); err != nil {
+	password := userPassword(bucket.Spec.CredentialMode, secret)
+	if err := policy.SetUser(secret.Data["user"], password); err != nil {
 		log.Error(err, "invalid credentials", "Secret.Name", secret.Name)
+		r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventInvalidCredentials,
+			"Secret %s: %s", secret.Name, err)
 		return ctrl.Result{}, err
 	}
 
-	if err := r.MinioClient.PolicyReconcile(ctx, policy); err != nil {
+	policyChanges, err := minioClient.PolicyReconcile(ctx, policy)
+	if errors.Is(err, minio.ErrPolicyNotManaged) {
+		log.Info("Refusing to manage existing canned policy", "Policy.Name", policy.Name)
+		r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventAdoptionRefused,
+			"Canned policy %s can not be managed: %s", policy.Name, err)
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeAvailableBucket,
+			Status: metav1.ConditionFalse, Reason: "AdoptionRefused",
+			Message: fmt.Sprintf("Canned policy %s can not be managed: %s", policy.Name, err)})
+		if err := r.Status().Update(ctx, bucket); err != nil {
+			log.Error(err, "Failed to update Bucket status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	} else if err != nil {
 		log.Error(err, "Failed to create Bucket user, policy and attach")
 		return ctrl.Result{}, err
 	}
+	recordPolicyChanges(r.Recorder, bucket, policy.Name, policyChanges)
+	drift.addPolicyChanges(policyChanges)
+	if bucket.Spec.CredentialMode == miniov1alpha1.CredentialModeSTS {
+		userReplaced := policyChanges.PolicyCreated || policyChanges.UserCreated || policyChanges.UserRecreated
+		if refreshed, err := refreshSTSCredentials(ctx, minioClient, bucket.Annotations, bucket.Spec.STS,
+			&bucket.Status.Credentials, secret, userReplaced, now); err != nil {
+			log.Error(err, "Failed to issue temporary credentials", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		} else if refreshed {
+			log.Info("Refreshing temporary credentials", "Secret.Name", secret.Name)
+			if err := r.Update(ctx, secret); err != nil {
+				log.Error(err, "Failed to update Secret with the temporary credentials", "Secret.Name", secret.Name)
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventCredentialsRefreshed,
+				"Issued temporary credentials in secret %s, valid until %s",
+				secret.Name, bucket.Status.Credentials.ExpirationTime.UTC().Format(time.RFC3339))
+		}
+	}
+	if changed, err := reconcileOverlap(ctx, minioClient, bucket.Status.Credentials, secret, now); errors.Is(err,
+		minio.ErrServiceAccountNotOwned) {
+		log.Info("Refusing the previous credentials of the Secret", "Secret.Name", secret.Name, "reason", err.Error())
+		r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventSecretConflict, "%s", err)
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeAvailableBucket,
+			Status: metav1.ConditionFalse, Reason: reasonSecretConflict,
+			Message: fmt.Sprintf("Secret refused: %s", err)})
+		if err := r.Status().Update(ctx, bucket); err != nil {
+			log.Error(err, "Failed to update Bucket status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to reconcile the previous credentials", "Secret.Name", secret.Name)
+		return ctrl.Result{}, err
+	} else if changed {
+		log.Info("Removing the expired previous credentials", "Secret.Name", secret.Name)
+		if err := r.Update(ctx, secret); err != nil {
+			log.Error(err, "Failed to update Secret", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventPreviousCredsExpired,
+			"Removed the previous credentials of secret %s", secret.Name)
+	}
+	if changed, err := renderSecret(bucket.Spec.SecretTemplate,
+		secretData(minioClient, bucket.BucketName(), secret), secret); errors.Is(err, minio.ErrInvalidSecretTemplate) {
+		log.Error(err, "invalid secret template", "Secret.Name", secret.Name)
+		r.Recorder.Event(bucket, corev1.EventTypeWarning, eventInvalidSecretTemplate, err.Error())
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeAvailableBucket,
+			Status: metav1.ConditionFalse, Reason: reasonInvalidSecretTemplate,
+			Message: fmt.Sprintf("Secret template refused: %s", err)})
+		if err := r.Status().Update(ctx, bucket); err != nil {
+			log.Error(err, "Failed to update Bucket status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to render secret template", "Secret.Name", secret.Name)
+		return ctrl.Result{}, err
+	} else if changed {
+		log.Info("Rendering secret template", "Secret.Name", secret.Name)
+		if err := r.Update(ctx, secret); err != nil {
+			log.Error(err, "Failed to update Secret", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		}
+	}
 
 	// The following implementation will update the status
+	r.recordDrift(bucket, drift)
 	meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeAvailableBucket,
-		Status: metav1.ConditionTrue, Reason: "Reconciling",
+		Status: metav1.ConditionTrue, Reason: "Reconciling", ObservedGeneration: bucket.Generation,
 		Message: fmt.Sprintf("Bucket %s created successfully", bucket.Name)})
 
 	if err := r.Status().Update(ctx, bucket); err != nil {
@@ -257,12 +563,14 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	requeueForCredentials(&result, bucket.Status.Credentials, now)
+	requeueForResync(&result, r.ResyncInterval)
+	return result, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *BucketReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&miniov1alpha1.Bucket{}).
 		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(
 			func(ctx context.Context, cm client.Object) []ctrl.Request {
@@ -296,8 +604,201 @@ func (r *BucketReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				},
 			}),
 		).
-		Named("bucket").
-		Complete(r)
+		Named("bucket")
+	if r.AuthRetry == nil {
+		return b.Complete(r)
+	}
+	return b.WatchesRawSource(r.AuthRetry.Source()).Complete(r.AuthRetry.Wrap(r))
+}
+
+// recordDrift sets the DriftDetected condition and emits an event when MinIO was repaired.
+func (r *BucketReconciler) recordDrift(bucket *Bucket, drift *driftReport) {
+	if message := drift.message(); message != "" {
+		r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventDriftRepaired,
+			"%s of bucket %s", message, bucket.BucketName())
+	}
+	drift.setCondition(&bucket.Status.Conditions, bucket.Generation)
+}
+
+// resolveBucketName computes the name of the bucket in MinIO for a resource without a recorded one.
+func (r *BucketReconciler) resolveBucketName(bucket *Bucket) (string, error) {
+	available := meta.FindStatusCondition(bucket.Status.Conditions, typeAvailableBucket)
+	switch {
+	case bucket.Spec.ExternalName != "":
+		return bucket.Spec.ExternalName, nil
+	case available != nil && available.Reason != reasonInvalidBucketName:
+		// reconciled before names were recorded, keep the historical name
+		return bucket.BucketName(), nil
+	}
+	return r.Naming.BucketName(bucket.Namespace, bucket.Name)
+}
+
+// deleteExternalResources removes the bucket and its users from MinIO according to the
+// deletion policy of the bucket. It returns a non zero result while the deletion is in progress.
+func (r *BucketReconciler) deleteExternalResources(
+	ctx context.Context, minioClient minio.Client, bucket *Bucket,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	if bucket.Status.BucketName == "" {
+		available := meta.FindStatusCondition(bucket.Status.Conditions, typeAvailableBucket)
+		if available == nil || available.Reason == reasonInvalidBucketName {
+			// the name was never resolved, BucketName would designate a bucket the resource never managed
+			log.Info("Bucket name was never resolved, leaving MinIO untouched")
+			return ctrl.Result{}, nil
+		}
+		// reconciled before names were recorded, the historical name designates the bucket
+		bucket.Status.BucketName = bucket.BucketName()
+	}
+	// never delete a bucket this resource does not manage, adoption may have been refused,
+	// the claim is only checked so that nothing is written to a bucket being deleted
+	adopt := bucket.Spec.ExternalName == ""
+	err := minioClient.BucketCheckClaim(ctx, bucket.BucketName(), bucket.Owner(), adopt)
+	if errors.Is(err, minio.ErrBucketClaimed) || errors.Is(err, minio.ErrBucketNotAdopted) {
+		log.Info("Bucket is not managed by the resource, leaving it untouched", "Bucket.Name", bucket.BucketName())
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to check Bucket claim", "Bucket.Name", bucket.BucketName())
+		return ctrl.Result{}, err
+	}
+
+	switch bucket.Spec.DeletionPolicy {
+	case miniov1alpha1.DeletionRetain:
+		log.Info("Retaining Bucket and associated users and policies", "Bucket.Name", bucket.BucketName())
+		if err := minioClient.BucketRelease(ctx, bucket.BucketName(), bucket.Owner()); err != nil {
+			log.Error(err, "Failed to release Bucket", "Bucket.Name", bucket.BucketName())
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventBucketRetained,
+			"Retained bucket %s and its users", bucket.BucketName())
+		return ctrl.Result{}, nil
+	case miniov1alpha1.DeletionForceDelete:
+		removed, err := minioClient.BucketPurge(ctx, bucket.BucketName(), purgeBatchSize)
+		if removed > 0 {
+			bucket.Status.PurgedObjects += int64(removed)
+			log.Info("Purging Bucket", "Bucket.Name", bucket.BucketName(), "Purged", bucket.Status.PurgedObjects)
+			r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventBucketPurging,
+				"Removed %d object versions", bucket.Status.PurgedObjects)
+			meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeDeletingBucket,
+				Status: metav1.ConditionTrue, Reason: "Purging",
+				Message: fmt.Sprintf("Removed %d object versions", bucket.Status.PurgedObjects)})
+		}
+		switch retained := errors.Is(err, minio.ErrObjectsRetained); {
+		case retained && removed == 0:
+			// the retention cannot be bypassed, wait for it to expire
+			log.Info("Bucket holds retained objects, deletion is blocked", "Bucket.Name", bucket.BucketName())
+			r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventDeleteBlocked,
+				"Bucket %s holds object versions under retention or legal hold", bucket.BucketName())
+			meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeDeletionBlockedBucket,
+				Status: metav1.ConditionTrue, Reason: "ObjectsRetained",
+				Message: "Object versions are under compliance retention or legal hold, " +
+					"the deletion resumes once it is lifted"})
+			return r.updateDeletionStatus(ctx, bucket, ctrl.Result{RequeueAfter: time.Minute}, nil)
+		case err != nil && !retained:
+			log.Error(err, "Failed purging bucket", "Bucket.Name", bucket.BucketName())
+			r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventDeleteFailed, "Failed to purge bucket: %s", err)
+			if removed == 0 {
+				return ctrl.Result{}, err
+			}
+			// keep the progress of the purge before retrying
+			return r.updateDeletionStatus(ctx, bucket, ctrl.Result{}, err)
+		case removed > 0:
+			return r.updateDeletionStatus(ctx, bucket, ctrl.Result{RequeueAfter: time.Second}, nil)
+		}
+	}
+
+	log.Info("Deleting Bucket", "Bucket.Name", bucket.BucketName())
+	if err := minioClient.BucketDelete(ctx, bucket.BucketName()); errors.Is(err, minio.ErrBucketNotEmpty) {
+		// deleting data is never done implicitly, report it and wait for the user to act
+		log.Info("Bucket is not empty, deletion is blocked", "Bucket.Name", bucket.BucketName())
+		r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventDeleteBlocked,
+			"Bucket %s is not empty, empty it or change the deletion policy", bucket.BucketName())
+		meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{Type: typeDeletionBlockedBucket,
+			Status: metav1.ConditionTrue, Reason: "BucketNotEmpty",
+			Message: "Bucket is not empty, empty it or change the deletion policy to ForceDelete or Retain"})
+		return r.updateDeletionStatus(ctx, bucket, ctrl.Result{RequeueAfter: time.Minute}, nil)
+	} else if err != nil {
+		// if fail to delete the external dependency here, return with error
+		// so that it can be retried.
+		log.Error(err, "Failed deleting bucket", "Bucket.Name", bucket.BucketName())
+		r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventDeleteFailed, "Failed to delete bucket: %s", err)
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Deleting associated users and policies", "Bucket.Name", bucket.BucketName())
+	// canned policies created before the marker was recorded are recognized by the user of the secret
+	user := ""
+	if secret, err := r.getSecret(ctx, bucket); err == nil {
+		user = string(secret.Data["user"])
+	} else if !apierrors.IsNotFound(err) {
+		log.Error(err, "Failed to get Secret", "Bucket.Name", bucket.BucketName())
+		return ctrl.Result{}, err
+	}
+	if err := minioClient.PolicyDelete(ctx, bucket.BucketName(), user); err != nil {
+		log.Error(err, "Failed deleting associated users and policies", "Bucket.Name", bucket.BucketName())
+		r.Recorder.Eventf(bucket, corev1.EventTypeWarning, eventDeleteFailed,
+			"Failed to delete the users and policies: %s", err)
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventBucketDeleted, "Deleted bucket %s", bucket.BucketName())
+	return ctrl.Result{}, nil
+}
+
+// updateDeletionStatus persists the status of a bucket being deleted before returning the given result.
+func (r *BucketReconciler) updateDeletionStatus(
+	ctx context.Context, bucket *Bucket, result ctrl.Result, err error,
+) (ctrl.Result, error) {
+	if updateErr := r.Status().Update(ctx, bucket); updateErr != nil {
+		log.FromContext(ctx).Error(updateErr, "Failed to update Bucket status")
+		return ctrl.Result{}, errors.Join(err, updateErr)
+	}
+	return result, err
+}
+
+// reconcileQuota applies the quota of the bucket and reports its usage in the status,
+// the status is not persisted and is left to the caller.
+func (r *BucketReconciler) reconcileQuota(ctx context.Context, minioClient minio.Client, bucket *Bucket) error {
+	log := log.FromContext(ctx)
+
+	quota := bucket.Spec.Quota
+	if quota == nil {
+		bucket.Status.Quota, bucket.Status.Usage = nil, nil
+		meta.RemoveStatusCondition(&bucket.Status.Conditions, typeQuotaWarningBucket)
+		return nil
+	}
+	size := quota.Size.Value()
+	if size < 0 {
+		return fmt.Errorf("invalid quota size %s", quota.Size.String())
+	}
+	if changed, err := minioClient.BucketQuotaReconcile(ctx, bucket.BucketName(), uint64(size)); err != nil {
+		return err
+	} else if changed {
+		log.Info("Reconciled bucket quota", "Quota.Size", quota.Size.String())
+		r.Recorder.Eventf(bucket, corev1.EventTypeNormal, eventQuotaUpdated, "Set the quota to %s", quota.Size.String())
+	}
+	if size == 0 {
+		bucket.Status.Quota, bucket.Status.Usage = nil, nil
+		meta.RemoveStatusCondition(&bucket.Status.Conditions, typeQuotaWarningBucket)
+		return nil
+	}
+
+	usage, err := minioClient.BucketUsage(ctx, bucket.BucketName())
+	if err != nil {
+		return err
+	}
+	bucket.Status.Quota = resource.NewQuantity(size, resource.BinarySI)
+	bucket.Status.Usage = resource.NewQuantity(int64(usage), resource.BinarySI)
+
+	percentage := int64(quota.WarningPercentageOrDefault())
+	condition := metav1.Condition{
+		Type: typeQuotaWarningBucket, Status: metav1.ConditionFalse, Reason: "UsageBelowThreshold",
+		Message: fmt.Sprintf("Bucket uses %s out of %s", bucket.Status.Usage, bucket.Status.Quota),
+	}
+	if int64(usage)*100 >= size*percentage {
+		condition.Status, condition.Reason = metav1.ConditionTrue, "UsageAboveThreshold"
+	}
+	meta.SetStatusCondition(&bucket.Status.Conditions, condition)
+	return nil
 }
 
 func (r *BucketReconciler) getSecret(ctx context.Context, bucket *Bucket) (*corev1.Secret, error) {
@@ -311,7 +812,7 @@ func (r *BucketReconciler) getSecret(ctx context.Context, bucket *Bucket) (*core
 }
 
 func (r *BucketReconciler) secretForBucket(bucket *Bucket) (*corev1.Secret, error) {
-	user, err := minio.GenerateAccessKey(0, nil)
+	user, err := minio.GenerateUser()
 	if err != nil {
 		return nil, err
 	}
@@ -330,6 +831,10 @@ func (r *BucketReconciler) secretForBucket(bucket *Bucket) (*corev1.Secret, erro
 			"password": password,
 		},
 	}
+	// the password is moved out of the credentials in STS mode
+	if _, err := switchCredentialMode(bucket.Spec.CredentialMode, secret); err != nil {
+		return nil, err
+	}
 	// Set the ownerRef for the Deployment
 	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/owners-dependents/
 	if err := ctrl.SetControllerReference(bucket, secret, r.Scheme); err != nil {
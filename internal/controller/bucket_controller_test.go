@@ -18,11 +18,17 @@ package controller
 
 import (
 	"context"
+	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"k8s.io/apimachinery/pkg/api/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,56 +39,345 @@ import (
 
 var _ = Describe("Bucket Controller", func() {
 	Context("When reconciling a resource", func() {
-		const resourceName = "test-resource"
+		const (
+			resourceName = "test-resource"
+			secretName   = "test-resource-credentials"
+			bucketName   = "default.test-resource"
+		)
 
 		ctx := context.Background()
 
 		typeNamespacedName := types.NamespacedName{
 			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+			Namespace: "default",
+		}
+		var (
+			fake                 *minio.Fake
+			recorder             *record.FakeRecorder
+			controllerReconciler *BucketReconciler
+		)
+
+		// reconcile runs the reconciliation the given number of times, the first ones
+		// register the finalizer, create the bucket and generate the secret.
+		reconcileTimes := func(times int) {
+			for range times {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+		getSecret := func() *corev1.Secret {
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: "default"}, secret)).To(Succeed())
+			return secret
 		}
-		bucket := &miniov1alpha1.Bucket{}
 
 		BeforeEach(func() {
-			By("creating the custom resource for the Kind Bucket")
-			err := k8sClient.Get(ctx, typeNamespacedName, bucket)
-			if err != nil && errors.IsNotFound(err) {
-				resource := &miniov1alpha1.Bucket{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      resourceName,
-						Namespace: "default",
-					},
-					Spec: miniov1alpha1.BucketSpec{
-						Policy: "private",
-					},
-				}
-				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			fake = minio.NewFake()
+			recorder = record.NewFakeRecorder(100)
+			controllerReconciler = &BucketReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+				Clients:  minio.NewRegistry(fake),
 			}
+
+			By("creating the custom resource for the Kind Bucket")
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: miniov1alpha1.BucketSpec{
+					SecretName: secretName,
+					Policy:     miniov1alpha1.PolicyDownload,
+				},
+			})).To(Succeed())
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
+			By("Cleanup the specific resource instance Bucket")
 			resource := &miniov1alpha1.Bucket{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				controllerutil.RemoveFinalizer(resource, finalizerName)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+			}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "default"}}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+		})
+
+		It("should create the bucket, its policy and its user", func() {
+			reconcileTimes(3)
+
+			bucket, ok := fake.Bucket(bucketName)
+			Expect(ok).To(BeTrue())
+			Expect(bucket.Owner).To(Equal("default/" + resourceName))
+			Expect(bucket.Policy).To(Equal(miniov1alpha1.PolicyDownload))
+
+			secret := getSecret()
+			user, ok := fake.User(string(secret.Data["user"]))
+			Expect(ok).To(BeTrue())
+			Expect(user.Password).To(Equal(string(secret.Data["password"])))
+			Expect(user.Policies).To(ConsistOf(bucketName))
+			_, ok = fake.CannedPolicy(bucketName)
+			Expect(ok).To(BeTrue())
+
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(finalizerName))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeAvailableBucket)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal " + eventBucketCreated + " ")))
+		})
+
+		It("should repair the anonymous policy changed behind the controller", func() {
+			reconcileTimes(3)
+			bucket, _ := fake.Bucket(bucketName)
+			bucket.Policy = miniov1alpha1.PolicyPublic
+			fake.SetBucket(bucketName, bucket)
+
+			reconcileTimes(1)
+			bucket, _ = fake.Bucket(bucketName)
+			Expect(bucket.Policy).To(Equal(miniov1alpha1.PolicyDownload))
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeDriftDetected)).To(BeTrue())
+		})
+
+		It("should leave an object lock it does not manage untouched", func() {
+			reconcileTimes(3)
+			bucket, _ := fake.Bucket(bucketName)
+			bucket.ObjectLocking = true
+			fake.SetBucket(bucketName, bucket)
+
+			reconcileTimes(1)
+			bucket, _ = fake.Bucket(bucketName)
+			Expect(bucket.ObjectLocking).To(BeTrue())
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeObjectLockBucket)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("ObjectLockUnmanaged"))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeAvailableBucket)).To(BeTrue())
+		})
+
+		It("should rotate the password of the user on request", func() {
+			reconcileTimes(3)
+			previous := getSecret()
+
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Annotations = map[string]string{miniov1alpha1.AnnotationRotateCredentials: "now"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			reconcileTimes(1)
+
+			secret := getSecret()
+			Expect(secret.Data["user"]).To(Equal(previous.Data["user"]))
+			Expect(secret.Data["password"]).NotTo(Equal(previous.Data["password"]))
+			user, _ := fake.User(string(secret.Data["user"]))
+			Expect(user.Password).To(Equal(string(secret.Data["password"])))
+		})
+
+		It("should remove the bucket and its user before releasing the finalizer", func() {
+			reconcileTimes(3)
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("blocking the deletion while the bucket is not empty")
+			bucket, _ := fake.Bucket(bucketName)
+			bucket.Objects = 1
+			fake.SetBucket(bucketName, bucket)
+			reconcileTimes(1)
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(fake.Buckets()).To(ConsistOf(bucketName))
+			blocked := meta.FindStatusCondition(resource.Status.Conditions, typeDeletionBlockedBucket)
+			Expect(blocked).NotTo(BeNil())
+			Expect(blocked.Reason).To(Equal("BucketNotEmpty"))
+
+			bucket.Objects = 0
+			fake.SetBucket(bucketName, bucket)
+			reconcileTimes(1)
+			Expect(fake.Buckets()).To(BeEmpty())
+			Expect(fake.Users()).To(BeEmpty())
+			_, ok := fake.CannedPolicy(bucketName)
+			Expect(ok).To(BeFalse())
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should remove a bucket reconciled before its name was recorded", func() {
+			reconcileTimes(3)
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Status.BucketName = ""
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			reconcileTimes(1)
+			Expect(fake.Buckets()).To(BeEmpty())
+			Expect(fake.Users()).To(BeEmpty())
+			_, ok := fake.CannedPolicy(bucketName)
+			Expect(ok).To(BeFalse())
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should purge the objects of the bucket when force deleting it", func() {
+			reconcileTimes(3)
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DeletionPolicy = miniov1alpha1.DeletionForceDelete
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			bucket, _ := fake.Bucket(bucketName)
+			bucket.Objects = 3
+			fake.SetBucket(bucketName, bucket)
+
+			By("recording the progress of the purge")
+			reconcileTimes(1)
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.PurgedObjects).To(Equal(int64(3)))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeDeletingBucket)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal " + eventBucketPurging + " ")))
+
+			By("removing the bucket once it is empty")
+			reconcileTimes(1)
+			Expect(fake.Buckets()).To(BeEmpty())
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should report the objects under retention blocking a force deletion", func() {
+			reconcileTimes(3)
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DeletionPolicy = miniov1alpha1.DeletionForceDelete
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			bucket, _ := fake.Bucket(bucketName)
+			bucket.Retained = 1
+			fake.SetBucket(bucketName, bucket)
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+			Expect(fake.Buckets()).To(ConsistOf(bucketName))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			blocked := meta.FindStatusCondition(resource.Status.Conditions, typeDeletionBlockedBucket)
+			Expect(blocked).NotTo(BeNil())
+			Expect(blocked.Reason).To(Equal("ObjectsRetained"))
+			Eventually(recorder.Events).Should(Receive(HavePrefix("Warning " + eventDeleteBlocked + " ")))
 
-			By("Cleanup the specific resource instance Bucket")
+			By("resuming the deletion once the retention expires")
+			bucket.Retained = 0
+			fake.SetBucket(bucketName, bucket)
+			reconcileTimes(1)
+			Expect(fake.Buckets()).To(BeEmpty())
+		})
+
+		It("should release a retained bucket and keep its user", func() {
+			reconcileTimes(3)
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DeletionPolicy = miniov1alpha1.DeletionRetain
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			secret := getSecret()
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			reconcileTimes(1)
+			bucket, ok := fake.Bucket(bucketName)
+			Expect(ok).To(BeTrue())
+			Expect(bucket.Owner).To(BeEmpty())
+			_, ok = fake.User(string(secret.Data["user"]))
+			Expect(ok).To(BeTrue())
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		})
-		It("should successfully reconcile the resource", func() {
-			By("Reconciling the created resource")
-			controllerReconciler := &BucketReconciler{
-				Client:      k8sClient,
-				Scheme:      k8sClient.Scheme(),
-				MinioClient: minio.NewStub(),
-			}
 
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
-				NamespacedName: typeNamespacedName,
+		It("should release the finalizer once the connection of the bucket is deleted", func() {
+			orphan := types.NamespacedName{Name: "orphan", Namespace: "default"}
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: orphan.Name, Namespace: orphan.Namespace, Finalizers: []string{finalizerName},
+				},
+				Spec: miniov1alpha1.BucketSpec{ConnectionRef: &miniov1alpha1.ConnectionReference{Name: "deleted"}},
+			})).To(Succeed())
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, orphan, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: orphan})
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(ctx, orphan, resource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning " + eventConnectionGone + " ")))
+		})
+
+		It("should retry once MinIO recovers", func() {
+			failure := errors.New("connection refused")
+			fake.SetError("BucketCreate", failure)
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(MatchError(failure))
+			Expect(fake.Buckets()).To(BeEmpty())
+
+			fake.SetError("BucketCreate", nil)
+			reconcileTimes(1)
+			Expect(fake.Buckets()).To(ConsistOf(bucketName))
+		})
+
+		It("should emit a warning event when no valid bucket name can be computed", func() {
+			naming, err := minio.NewNamingStrategy("{{ .Namespace }}_{{ .Name }}", "")
+			Expect(err).NotTo(HaveOccurred())
+			controllerReconciler.Naming = naming
+
+			invalid := types.NamespacedName{Name: "invalid-name", Namespace: "default"}
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: invalid.Name, Namespace: invalid.Namespace},
+			})).To(Succeed())
+			DeferCleanup(func() {
+				resource := &miniov1alpha1.Bucket{}
+				Expect(k8sClient.Get(ctx, invalid, resource)).To(Succeed())
+				controllerutil.RemoveFinalizer(resource, finalizerName)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 			})
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: invalid})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning " + eventInvalidBucketName + " ")))
+			Expect(fake.Buckets()).To(BeEmpty())
+		})
+
+		It("should leave MinIO untouched when deleting a resource without a resolved name", func() {
+			naming, err := minio.NewNamingStrategy("{{ .Namespace }}_{{ .Name }}", "")
+			Expect(err).NotTo(HaveOccurred())
+			controllerReconciler.Naming = naming
+
+			invalid := types.NamespacedName{Name: "invalid-name", Namespace: "default"}
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: invalid.Name, Namespace: invalid.Namespace},
+				Spec:       miniov1alpha1.BucketSpec{DeletionPolicy: miniov1alpha1.DeletionForceDelete},
+			})).To(Succeed())
+			// the bucket the default name designates belongs to someone else
+			fake.SetBucket("default.invalid-name", minio.FakeBucket{Objects: 1})
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: invalid})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &miniov1alpha1.Bucket{}
+			Expect(k8sClient.Get(ctx, invalid, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(finalizerName))
+			Expect(resource.Status.BucketName).To(BeEmpty())
+
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: invalid})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			err = k8sClient.Get(ctx, invalid, resource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			bucket, ok := fake.Bucket("default.invalid-name")
+			Expect(ok).To(BeTrue())
+			Expect(bucket.Objects).To(Equal(1))
+			Expect(bucket.Owner).To(BeEmpty())
+			Expect(fake.Calls("BucketPurge")).To(BeZero())
 		})
 	})
 })
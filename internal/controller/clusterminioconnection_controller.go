@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+// ClusterMinioConnectionReconciler reconciles a ClusterMinioConnection object
+type ClusterMinioConnectionReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Clients *minio.Registry
+	// Namespace in which the secrets are read when their reference has no namespace
+	Namespace string
+}
+
+// +kubebuilder:rbac:groups=minio.ixday.github.io,resources=clusterminioconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups=minio.ixday.github.io,resources=clusterminioconnections/status,verbs=get;update;patch
+
+// Reconcile registers the client of the connection and reports the health of the server.
+func (r *ClusterMinioConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	key := minio.ConnectionKey(miniov1alpha1.KindClusterMinioConnection, "", req.Name)
+	connection := &miniov1alpha1.ClusterMinioConnection{}
+	if err := r.Get(ctx, req.NamespacedName, connection); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Clients.Delete(key)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get connection")
+		return ctrl.Result{}, err
+	}
+
+	condition := reconcileConnection(ctx, r.Client, r.Clients, key, &connection.Spec, r.secretNamespace(connection))
+	condition.ObservedGeneration = connection.Generation
+	meta.SetStatusCondition(&connection.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, connection); err != nil {
+		log.Error(err, "Failed to update connection status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: connectionHealthInterval}, nil
+}
+
+// secretNamespace is the namespace of the credentials, the CA bundle is looked up in the same one.
+func (r *ClusterMinioConnectionReconciler) secretNamespace(connection *miniov1alpha1.ClusterMinioConnection) string {
+	if namespace := connection.Spec.CredentialsSecretRef.Namespace; namespace != "" {
+		return namespace
+	}
+	return r.Namespace
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterMinioConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&miniov1alpha1.ClusterMinioConnection{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, secret client.Object) []ctrl.Request {
+				connections := &miniov1alpha1.ClusterMinioConnectionList{}
+				if err := r.List(ctx, connections); err != nil {
+					return nil
+				}
+				requests := []ctrl.Request{}
+				for _, connection := range connections.Items {
+					if r.secretNamespace(&connection) == secret.GetNamespace() &&
+						referencesSecret(&connection.Spec, secret.GetName()) {
+						requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{
+							Name: connection.Name,
+						}})
+					}
+				}
+				return requests
+			}),
+		).
+		Named("clusterminioconnection").
+		Complete(r)
+}
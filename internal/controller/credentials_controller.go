@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/IxDay/internal/minio"
+)
+
+// AuthRetry remembers the requests whose reconciliation was refused by MinIO because
+// of the credentials, they are enqueued again once the credentials are reloaded
+// instead of waiting for their backoff to expire.
+type AuthRetry struct {
+	mutex     sync.Mutex
+	pending   map[types.NamespacedName]struct{}
+	events    chan event.GenericEvent
+	newObject func() client.Object
+}
+
+// NewAuthRetry tracks the requests of a controller, newObject returns an empty object of its kind.
+func NewAuthRetry(newObject func() client.Object) *AuthRetry {
+	return &AuthRetry{
+		pending:   map[types.NamespacedName]struct{}{},
+		events:    make(chan event.GenericEvent),
+		newObject: newObject,
+	}
+}
+
+// Wrap records the requests failing with an authentication error.
+func (a *AuthRetry) Wrap(reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		result, err := reconciler.Reconcile(ctx, req)
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		if minio.IsAuthError(err) {
+			a.pending[req.NamespacedName] = struct{}{}
+		} else {
+			delete(a.pending, req.NamespacedName)
+		}
+		return result, err
+	})
+}
+
+// Source feeds the retried requests to the controller.
+func (a *AuthRetry) Source() source.Source {
+	return source.Channel(a.events, &handler.EnqueueRequestForObject{})
+}
+
+// Retry enqueues the pending requests again.
+func (a *AuthRetry) Retry(ctx context.Context) {
+	a.mutex.Lock()
+	pending := a.pending
+	a.pending = map[types.NamespacedName]struct{}{}
+	a.mutex.Unlock()
+
+	for key := range pending {
+		object := a.newObject()
+		object.SetName(key.Name)
+		object.SetNamespace(key.Namespace)
+		select {
+		case a.events <- event.GenericEvent{Object: object}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CredentialsReconciler reloads the default MinIO client when the connection secret changes
+type CredentialsReconciler struct {
+	client.Client
+	Clients *minio.Registry
+	// SecretName and Namespace locate the connection secret
+	SecretName, Namespace string
+	// TLS holds the settings of the command line, the secret takes precedence
+	TLS minio.ClientOptions
+	// Retries are triggered once a new client is in place
+	Retries []*AuthRetry
+}
+
+// Reconcile swaps the default client, reconcilers keep running with the client they already resolved.
+func (r *CredentialsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, req.NamespacedName, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// keep the current client, it may still be valid
+			log.Info("Connection secret not found, keeping the current MinIO client")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get connection secret")
+		return ctrl.Result{}, err
+	}
+
+	options, err := minio.OptionsFromSecret(secret)
+	if err != nil {
+		log.Error(err, "Invalid connection secret, keeping the current MinIO client")
+		return ctrl.Result{}, nil
+	}
+	changed, err := r.Clients.SetDefault(options.WithTLS(r.TLS))
+	if err != nil {
+		log.Error(err, "Failed to instantiate MinIO client from secret")
+		return ctrl.Result{}, err
+	}
+	if changed {
+		log.Info("Reloaded MinIO client", "Endpoint", options.Endpoint)
+		for _, retry := range r.Retries {
+			retry.Retry(ctx)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CredentialsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Secret{}, builder.WithPredicates(predicate.NewPredicateFuncs(
+			func(object client.Object) bool {
+				return object.GetNamespace() == r.Namespace && object.GetName() == r.SecretName
+			}),
+		)).
+		Named("credentials").
+		Complete(r)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/IxDay/internal/minio"
+)
+
+var _ = Describe("Credentials Controller", func() {
+	Context("When reconciling the connection secret", func() {
+		const resourceName = "test-credentials"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the connection secret")
+			err := k8sClient.Get(ctx, typeNamespacedName, &corev1.Secret{})
+			if err != nil && errors.IsNotFound(err) {
+				Expect(k8sClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+					Data: map[string][]byte{
+						"endpoint": []byte("127.0.0.1:1"),
+						"user":     []byte("admin"),
+						"password": []byte("password"),
+					},
+				})).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the connection secret")
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			})).To(Succeed())
+		})
+
+		It("should swap the default client only when the secret changes", func() {
+			clients := minio.NewRegistry(nil)
+			controllerReconciler := &CredentialsReconciler{
+				Client:     k8sClient,
+				Clients:    clients,
+				SecretName: resourceName,
+				Namespace:  "default",
+			}
+			request := reconcile.Request{NamespacedName: typeNamespacedName}
+
+			By("Loading the initial credentials")
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			initial, err := clients.Client(nil, "default")
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Reconciling an unchanged secret")
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients.Client(nil, "default")).To(BeIdenticalTo(initial))
+
+			By("Rotating the password")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, secret)).To(Succeed())
+			secret.Data["password"] = []byte("rotated")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients.Client(nil, "default")).NotTo(BeIdenticalTo(initial))
+		})
+
+		It("should keep the current client when the secret is invalid", func() {
+			clients := minio.NewRegistry(minio.NewStub())
+			initial, err := clients.Client(nil, "default")
+			Expect(err).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, secret)).To(Succeed())
+			delete(secret.Data, "password")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			controllerReconciler := &CredentialsReconciler{
+				Client:     k8sClient,
+				Clients:    clients,
+				SecretName: resourceName,
+				Namespace:  "default",
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients.Client(nil, "default")).To(BeIdenticalTo(initial))
+		})
+	})
+})
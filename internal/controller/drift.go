@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/IxDay/internal/minio"
+)
+
+const (
+	// typeDriftDetected records the MinIO state repaired by the last verification of an available resource
+	typeDriftDetected = "DriftDetected"
+	// reasonDriftRepaired is set when the MinIO state diverged from the resource and was repaired
+	reasonDriftRepaired = "DriftRepaired"
+	// reasonInSync is set when the last verification found MinIO matching the resource
+	reasonInSync = "InSync"
+)
+
+// driftReport collects what a reconciliation repaired in MinIO. Drift is only tracked
+// once the current generation of the resource has been made available, before that the
+// reconciliations create the MinIO state rather than repair it.
+type driftReport struct {
+	tracking bool
+	repaired []string
+}
+
+// newDriftReport tracks drift when the available condition was set for the current generation.
+func newDriftReport(conditions []metav1.Condition, availableType string, generation int64) *driftReport {
+	available := meta.FindStatusCondition(conditions, availableType)
+	return &driftReport{
+		tracking: available != nil && available.Status == metav1.ConditionTrue &&
+			available.ObservedGeneration == generation,
+	}
+}
+
+// add records a repair when changed is set.
+func (d *driftReport) add(changed bool, what string) {
+	if changed && d.tracking {
+		d.repaired = append(d.repaired, what)
+	}
+}
+
+// addPolicyChanges records the changes made by PolicyReconcile.
+func (d *driftReport) addPolicyChanges(changes minio.PolicyChanges) {
+	d.add(changes.PolicyCreated, "canned policy and attachment")
+	d.add(changes.PolicyUpdated, "canned policy document")
+	d.add(changes.UserCreated || changes.UserRecreated, "user")
+	d.add(changes.UsersDetached, "user attachment")
+	d.add(changes.IdentitiesUpdated, "LDAP identities")
+}
+
+// message describes the repairs, empty when nothing was repaired.
+func (d *driftReport) message() string {
+	if len(d.repaired) == 0 {
+		return ""
+	}
+	return "Repaired " + strings.Join(d.repaired, ", ")
+}
+
+// setCondition records the outcome of the verification in the conditions. A repair is
+// kept in the message of the following in sync verifications until the next drift.
+func (d *driftReport) setCondition(conditions *[]metav1.Condition, generation int64) {
+	if !d.tracking {
+		return
+	}
+	if message := d.message(); message != "" {
+		meta.SetStatusCondition(conditions, metav1.Condition{Type: typeDriftDetected,
+			Status: metav1.ConditionTrue, Reason: reasonDriftRepaired, ObservedGeneration: generation,
+			Message: message})
+		return
+	}
+	message := "MinIO matches the resource"
+	if previous := meta.FindStatusCondition(*conditions, typeDriftDetected); previous != nil {
+		if previous.Status == metav1.ConditionFalse {
+			// still in sync, the message may remember the last drift
+			message = previous.Message
+		} else {
+			message = fmt.Sprintf("%s, last drift: %s", message, previous.Message)
+		}
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{Type: typeDriftDetected,
+		Status: metav1.ConditionFalse, Reason: reasonInSync, ObservedGeneration: generation,
+		Message: message})
+}
+
+// requeueForResync schedules the next verification of the MinIO state, an earlier
+// requeue is kept. A zero interval disables the resync.
+func requeueForResync(result *ctrl.Result, interval time.Duration) {
+	if interval > 0 && (result.RequeueAfter == 0 || interval < result.RequeueAfter) {
+		result.RequeueAfter = interval
+	}
+}
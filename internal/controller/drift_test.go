@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/IxDay/internal/minio"
+)
+
+var _ = Describe("Drift detection", func() {
+	var conditions []metav1.Condition
+
+	BeforeEach(func() {
+		conditions = []metav1.Condition{{
+			Type: typeAvailableBucket, Status: metav1.ConditionTrue, Reason: "Reconciling", ObservedGeneration: 2,
+		}}
+	})
+
+	It("should not track the reconciliations of a new generation", func() {
+		drift := newDriftReport(conditions, typeAvailableBucket, 3)
+		drift.add(true, "anonymous policy")
+		Expect(drift.message()).To(BeEmpty())
+		drift.setCondition(&conditions, 3)
+		Expect(meta.FindStatusCondition(conditions, typeDriftDetected)).To(BeNil())
+	})
+
+	It("should record the repairs and remember them once in sync", func() {
+		drift := newDriftReport(conditions, typeAvailableBucket, 2)
+		drift.add(false, "versioning")
+		drift.add(true, "anonymous policy")
+		drift.addPolicyChanges(minio.PolicyChanges{PolicyUpdated: true, UserCreated: true})
+		drift.setCondition(&conditions, 2)
+		condition := meta.FindStatusCondition(conditions, typeDriftDetected)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(reasonDriftRepaired))
+		Expect(condition.Message).To(Equal("Repaired anonymous policy, canned policy document, user"))
+
+		for range 2 {
+			drift = newDriftReport(conditions, typeAvailableBucket, 2)
+			drift.setCondition(&conditions, 2)
+			condition = meta.FindStatusCondition(conditions, typeDriftDetected)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(reasonInSync))
+			Expect(condition.Message).To(Equal(
+				"MinIO matches the resource, last drift: Repaired anonymous policy, canned policy document, user"))
+		}
+	})
+
+	It("should keep the earliest requeue", func() {
+		result := ctrl.Result{}
+		requeueForResync(&result, 0)
+		Expect(result.RequeueAfter).To(BeZero())
+		requeueForResync(&result, 10*time.Minute)
+		Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+		result.RequeueAfter = time.Minute
+		requeueForResync(&result, 10*time.Minute)
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+})
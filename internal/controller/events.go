@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/IxDay/internal/minio"
+)
+
+// reasons of the events emitted on the resources, they are part of the API and must stay stable
+const (
+	eventBucketCreated         = "BucketCreated"
+	eventBucketAdopted         = "BucketAdopted"
+	eventAdoptionRefused       = "AdoptionRefused"
+	eventInvalidBucketName     = "InvalidBucketName"
+	eventBucketPolicyUpdated   = "BucketPolicyUpdated"
+	eventVersioningUpdated     = "VersioningUpdated"
+	eventLifecycleUpdated      = "LifecycleUpdated"
+	eventQuotaUpdated          = "QuotaUpdated"
+	eventObjectLockUpdated     = "ObjectLockUpdated"
+	eventObjectLockImmutable   = "ObjectLockImmutable"
+	eventEncryptionUpdated     = "EncryptionUpdated"
+	eventKMSNotConfigured      = "KMSNotConfigured"
+	eventBucketPurging         = "BucketPurging"
+	eventBucketDeleted         = "BucketDeleted"
+	eventBucketRetained        = "BucketRetained"
+	eventDeleteBlocked         = "DeleteBlocked"
+	eventDeleteFailed          = "DeleteFailed"
+	eventConnectionGone        = "ConnectionGone"
+	eventBucketNotFound        = "BucketNotFound"
+	eventSecretCreated         = "SecretCreated"
+	eventSecretConflict        = "SecretConflict"
+	eventInvalidCredentials    = "InvalidCredentials"
+	eventCredentialsRotated    = "CredentialsRotated"
+	eventPreviousCredsExpired  = "PreviousCredentialsExpired"
+	eventCredentialsRefreshed  = "CredentialsRefreshed"
+	eventInvalidSecretTemplate = "InvalidSecretTemplate"
+	eventAccessKeyExpired      = "AccessKeyExpired"
+	eventAccessKeyRevoked      = "AccessKeyRevoked"
+	eventPolicyCreated         = "PolicyCreated"
+	eventPolicyUpdated         = "PolicyUpdated"
+	eventPolicyOutOfBucket     = "OutOfBucket"
+	eventPolicyDeleted         = "PolicyDeleted"
+	eventUserCreated           = "UserCreated"
+	eventUserRecreated         = "UserRecreated"
+	eventUsersDetached         = "UsersDetached"
+	eventIdentitiesUpdated     = "IdentitiesUpdated"
+	eventGroupCreated          = "GroupCreated"
+	eventGroupEnabled          = "GroupEnabled"
+	eventGroupMembersUpdated   = "GroupMembersUpdated"
+	eventGroupPoliciesUpdated  = "GroupPoliciesUpdated"
+	eventGroupDeleted          = "GroupDeleted"
+	eventConnectionMismatch    = "ConnectionMismatch"
+	eventDriftRepaired         = "DriftRepaired"
+	eventOrphanFound           = "OrphanFound"
+	eventOrphanDeleted         = "OrphanDeleted"
+	eventOrphanDeleteFailed    = "OrphanDeleteFailed"
+)
+
+// recordPolicyChanges emits an event for each change made in MinIO by PolicyReconcile.
+func recordPolicyChanges(recorder record.EventRecorder, object runtime.Object, name string, changes minio.PolicyChanges) {
+	if changes.PolicyCreated {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventPolicyCreated, "Created policy %s and its user", name)
+	}
+	if changes.PolicyUpdated {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventPolicyUpdated, "Updated the statements of policy %s", name)
+	}
+	if changes.UserCreated {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventUserCreated, "Created the user of policy %s", name)
+	}
+	if changes.UserRecreated {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventUserRecreated,
+			"Replaced the user of policy %s to match the secret", name)
+	}
+	if changes.UsersDetached {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventUsersDetached,
+			"Detached policy %s from its users, it is bound to external identities", name)
+	}
+	if changes.IdentitiesUpdated {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventIdentitiesUpdated,
+			"Updated the LDAP identities bound to policy %s", name)
+	}
+}
+
+// recordGroupChanges emits an event for each change made in MinIO by GroupReconcile.
+func recordGroupChanges(recorder record.EventRecorder, object runtime.Object, name string, changes minio.GroupChanges) {
+	if changes.Created {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventGroupCreated, "Created group %s", name)
+	}
+	if changes.Enabled {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventGroupEnabled, "Enabled group %s", name)
+	}
+	if changes.MembersUpdated {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventGroupMembersUpdated, "Updated the members of group %s", name)
+	}
+	if changes.PoliciesUpdated {
+		recorder.Eventf(object, corev1.EventTypeNormal, eventGroupPoliciesUpdated,
+			"Updated the policies attached to group %s", name)
+	}
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+// GCMode selects what the orphan collector does with the orphans it finds.
+type GCMode string
+
+const (
+	// GCModeDisabled does not look for orphans
+	GCModeDisabled GCMode = "disabled"
+	// GCModeReport only reports the orphans through metrics and events
+	GCModeReport GCMode = "report"
+	// GCModeDelete removes the orphans from MinIO
+	GCModeDelete GCMode = "delete"
+)
+
+var (
+	errUnknownGCMode     = errors.New("unknown gc mode")
+	errInvalidGCInterval = errors.New("gc interval must be positive")
+)
+
+// ParseGCMode validates the mode given on the command line.
+func ParseGCMode(mode string) (GCMode, error) {
+	switch parsed := GCMode(mode); parsed {
+	case GCModeDisabled, GCModeReport, GCModeDelete:
+		return parsed, nil
+	}
+	return "", fmt.Errorf("%w %q", errUnknownGCMode, mode)
+}
+
+// kinds of the MinIO entities collected, used in the metrics and the events
+const (
+	orphanBucket = "bucket"
+	orphanPolicy = "policy"
+	orphanUser   = "user"
+)
+
+// OrphanCollector periodically looks for the buckets, canned policies and users left in
+// MinIO by the controller without a resource accounting for them, after a crash between
+// two MinIO calls or a resource removed without its finalizer. Only the entities the controller
+// marked at creation are considered: claimed buckets, canned policies carrying
+// minio.ManagedPolicyID and users named with minio.GeneratedUserPrefix. The connections pointing at
+// the same server are collected together, the entities of any of their resources are owned. It
+// assumes each MinIO server is managed by a single controller.
+type OrphanCollector struct {
+	// Reader lists the resources, it should bypass the cache so that a resource
+	// created meanwhile is not missed
+	Reader   client.Reader
+	Recorder record.EventRecorder
+	Clients  *minio.Registry
+	Mode     GCMode
+	Interval time.Duration
+	// SecretName and Namespace locate the connection secret, the default connection events are recorded on it
+	SecretName string
+	Namespace  string
+}
+
+var _ manager.LeaderElectionRunnable = &OrphanCollector{}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, a single replica collects the orphans.
+func (g *OrphanCollector) NeedLeaderElection() bool { return true }
+
+// Start implements manager.Runnable, the first collection waits for an interval so that
+// the resources created while the manager was down get reconciled.
+func (g *OrphanCollector) Start(ctx context.Context) error {
+	if g.Interval <= 0 {
+		return fmt.Errorf("%w: %s", errInvalidGCInterval, g.Interval)
+	}
+	ticker := time.NewTicker(g.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			g.Collect(ctx)
+		}
+	}
+}
+
+// Collect looks for the orphans of every server, and deletes them in delete mode. The orphans
+// of a server are reported on the first of its connections.
+func (g *OrphanCollector) Collect(ctx context.Context) {
+	log := log.FromContext(ctx).WithName("gc")
+	connections := g.Clients.Connections()
+	for _, keys := range serverConnections(connections) {
+		key, minioClient := keys[0], connections[keys[0]]
+		connection := connectionLabel(key)
+		// listed before the resources, MinIO entities created meanwhile always have their resource
+		inventory, err := minioClient.Inventory(ctx)
+		if err != nil {
+			log.Error(err, "Failed to list MinIO content", "connection", connection)
+			continue
+		}
+		owned, err := g.ownedResources(ctx, keys)
+		if err != nil {
+			log.Error(err, "Failed to list resources", "connection", connection)
+			continue
+		}
+		orphans := decideOrphans(inventory, owned)
+		counts := map[string]int{orphanBucket: 0, orphanPolicy: 0, orphanUser: 0}
+		object := g.connectionObject(key)
+		for _, orphan := range orphans {
+			counts[orphan.kind]++
+			if g.Mode != GCModeDelete {
+				log.Info("Found orphan", "connection", connection, "kind", orphan.kind, "name", orphan.name)
+				g.Recorder.Eventf(object, corev1.EventTypeWarning, eventOrphanFound,
+					"Found orphan %s %s", orphan.kind, orphan.name)
+				continue
+			}
+			if err := deleteOrphan(ctx, minioClient, orphan); err != nil {
+				log.Error(err, "Failed to delete orphan", "connection", connection, "kind", orphan.kind, "name", orphan.name)
+				g.Recorder.Eventf(object, corev1.EventTypeWarning, eventOrphanDeleteFailed,
+					"Failed to delete orphan %s %s: %s", orphan.kind, orphan.name, err)
+				continue
+			}
+			log.Info("Deleted orphan", "connection", connection, "kind", orphan.kind, "name", orphan.name)
+			g.Recorder.Eventf(object, corev1.EventTypeNormal, eventOrphanDeleted,
+				"Deleted orphan %s %s", orphan.kind, orphan.name)
+			orphansDeleted.WithLabelValues(orphan.kind).Inc()
+		}
+		for kind, count := range counts {
+			orphanResources.WithLabelValues(connection, kind).Set(float64(count))
+		}
+	}
+}
+
+// deleteOrphan removes the orphan from MinIO, buckets are only removed when empty and the
+// users attached to a canned policy are kept.
+func deleteOrphan(ctx context.Context, minioClient minio.Client, orphan orphan) error {
+	switch orphan.kind {
+	case orphanBucket:
+		return minioClient.BucketDelete(ctx, orphan.name)
+	case orphanPolicy:
+		return minioClient.CannedPolicyDelete(ctx, orphan.name)
+	case orphanUser:
+		return minioClient.UserDelete(ctx, orphan.name)
+	}
+	return fmt.Errorf("unknown orphan kind %q", orphan.kind)
+}
+
+// serverConnections groups the keys of the connections by server, each group is sorted.
+func serverConnections(connections map[string]minio.Client) [][]string {
+	servers := map[string][]string{}
+	for key, minioClient := range connections {
+		url := minioClient.Endpoint().URL
+		servers[url] = append(servers[url], key)
+	}
+	groups := [][]string{}
+	for _, url := range slices.Sorted(maps.Keys(servers)) {
+		groups = append(groups, slices.Sorted(slices.Values(servers[url])))
+	}
+	return groups
+}
+
+// ownedResources collects what the resources of the connections expect to find in MinIO.
+func (g *OrphanCollector) ownedResources(ctx context.Context, keys []string) (ownedResources, error) {
+	owned := ownedResources{buckets: map[string]string{}, policies: map[string]bool{}, users: map[string]bool{}}
+	buckets := &miniov1alpha1.BucketList{}
+	if err := g.Reader.List(ctx, buckets); err != nil {
+		return owned, err
+	}
+	bucketRefs := map[types.NamespacedName]*miniov1alpha1.ConnectionReference{}
+	for _, bucket := range buckets.Items {
+		bucketRefs[types.NamespacedName{Namespace: bucket.Namespace, Name: bucket.Name}] = bucket.Spec.ConnectionRef
+		if !slices.Contains(keys, connectionKey(bucket.Spec.ConnectionRef, bucket.Namespace)) {
+			continue
+		}
+		owned.buckets[bucket.Owner()] = bucket.BucketName()
+		owned.policies[bucket.BucketName()] = true
+		if bucket.Spec.SecretName == "" {
+			continue
+		}
+		if err := g.addUsers(ctx, owned, bucket.Namespace, bucket.Spec.SecretName); err != nil {
+			return owned, err
+		}
+	}
+
+	policies := &miniov1alpha1.PolicyList{}
+	if err := g.Reader.List(ctx, policies); err != nil {
+		return owned, err
+	}
+	for _, policy := range policies.Items {
+		ref := policy.Spec.ConnectionRef
+		if ref == nil {
+			ref = bucketRefs[types.NamespacedName{Namespace: policy.Namespace, Name: policy.Spec.BucketName}]
+		}
+		if !slices.Contains(keys, connectionKey(ref, policy.Namespace)) {
+			continue
+		}
+		owned.policies[policy.PolicyName()] = true
+		secretName := policy.Spec.SecretName
+		if secretName == "" {
+			secretName = policy.Name
+		}
+		if err := g.addUsers(ctx, owned, policy.Namespace, secretName); err != nil {
+			return owned, err
+		}
+	}
+	return owned, nil
+}
+
+// addUsers records the current and previous users of the generated secret.
+func (g *OrphanCollector) addUsers(ctx context.Context, owned ownedResources, namespace, name string) error {
+	secret := &corev1.Secret{}
+	err := g.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, key := range []string{"user", keyPreviousUser} {
+		if user := string(secret.Data[key]); user != "" {
+			owned.users[user] = true
+		}
+	}
+	return nil
+}
+
+// connectionObject returns the object the events of a connection are recorded on.
+func (g *OrphanCollector) connectionObject(key string) client.Object {
+	kind, rest, _ := strings.Cut(key, "/")
+	namespace, name, _ := strings.Cut(rest, "/")
+	meta := metav1.ObjectMeta{Namespace: namespace, Name: name}
+	switch kind {
+	case miniov1alpha1.KindMinioConnection:
+		return &miniov1alpha1.MinioConnection{ObjectMeta: meta}
+	case miniov1alpha1.KindClusterMinioConnection:
+		return &miniov1alpha1.ClusterMinioConnection{ObjectMeta: meta}
+	}
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: g.Namespace, Name: g.SecretName}}
+}
+
+// connectionLabel names the connection in the metrics and the logs.
+func connectionLabel(key string) string {
+	if key == "" {
+		return "default"
+	}
+	return key
+}
+
+type orphan struct {
+	kind, name string
+}
+
+// ownedResources is what the resources of the connections of a server expect to find in MinIO.
+type ownedResources struct {
+	// buckets maps the owners recorded by the claims to the name of their bucket
+	buckets  map[string]string
+	policies map[string]bool
+	users    map[string]bool
+}
+
+// decideOrphans returns the MinIO entities created by the controller that no resource accounts for:
+//   - claimed buckets whose owner no longer exists or uses another bucket;
+//   - managed canned policies without resource, a policy named after an unclaimed bucket is
+//     retained with it and a policy still attached to a group or an LDAP identity is in use;
+//   - generated users unknown to the secrets, neither member of a group nor attached to a
+//     canned policy which is kept.
+func decideOrphans(inventory minio.Inventory, owned ownedResources) []orphan {
+	orphans := []orphan{}
+	for _, name := range slices.Sorted(maps.Keys(inventory.Buckets)) {
+		if owner := inventory.Buckets[name]; owner != "" && owned.buckets[owner] != name {
+			orphans = append(orphans, orphan{kind: orphanBucket, name: name})
+		}
+	}
+
+	grouped, members := map[string]bool{}, map[string]bool{}
+	for _, group := range inventory.Groups {
+		for _, policy := range group.Policies {
+			grouped[policy] = true
+		}
+		for _, member := range group.Members {
+			members[member] = true
+		}
+	}
+	orphaned := map[string]bool{}
+	for _, name := range slices.Sorted(maps.Keys(inventory.Policies)) {
+		owner, isBucket := inventory.Buckets[name]
+		retained := isBucket && owner == ""
+		if inventory.Policies[name] && !owned.policies[name] && !retained &&
+			!grouped[name] && !inventory.LDAPBound[name] {
+			orphaned[name] = true
+			orphans = append(orphans, orphan{kind: orphanPolicy, name: name})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(inventory.Users)) {
+		kept := func(policy string) bool { return !orphaned[policy] }
+		if !minio.IsGeneratedUser(name) || owned.users[name] || members[name] ||
+			slices.ContainsFunc(inventory.Users[name], kept) {
+			continue
+		}
+		orphans = append(orphans, orphan{kind: orphanUser, name: name})
+	}
+	return orphans
+}
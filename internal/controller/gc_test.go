@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/IxDay/internal/minio"
+)
+
+var _ = Describe("Orphan collector", func() {
+	const (
+		liveUser     = minio.GeneratedUserPrefix + "AAAAAAAAAAAAAAAA"
+		orphanedUser = minio.GeneratedUserPrefix + "BBBBBBBBBBBBBBBB"
+		idleUser     = minio.GeneratedUserPrefix + "CCCCCCCCCCCCCCCC"
+		memberUser   = minio.GeneratedUserPrefix + "DDDDDDDDDDDDDDDD"
+		sharedUser   = minio.GeneratedUserPrefix + "EEEEEEEEEEEEEEEE"
+		foreignUser  = "CCCCCCCCCCCCCCCCCCCC"
+	)
+
+	It("should only report the entities marked by the controller", func() {
+		inventory := minio.Inventory{
+			Buckets: map[string]string{
+				"default.live":     "default/live",
+				"default.gone":     "default/gone",
+				"default.retained": "",
+			},
+			Policies: map[string]bool{
+				"default.live": true, "default.gone": true, "default.retained": true,
+				"default.live.reader": true, "default.live.stale": true,
+				"default.live.grouped": true, "default.live.ldap": true,
+				"default.live.foreign": false, "readwrite": false,
+			},
+			Users: map[string][]string{
+				liveUser:     {"default.live"},
+				orphanedUser: {"default.live.stale"},
+				idleUser:     nil,
+				memberUser:   nil,
+				sharedUser:   {"default.live.stale", "readwrite"},
+				foreignUser:  {"default.live.stale"},
+				"admin":      nil,
+			},
+			Groups: []minio.Group{
+				{Name: "team", Members: []string{memberUser}, Policies: []string{"default.live.grouped"}},
+			},
+			LDAPBound: map[string]bool{"default.live.ldap": true},
+		}
+		owned := ownedResources{
+			buckets:  map[string]string{"default/live": "default.live"},
+			policies: map[string]bool{"default.live": true, "default.live.reader": true},
+			users:    map[string]bool{liveUser: true},
+		}
+		Expect(decideOrphans(inventory, owned)).To(Equal([]orphan{
+			{kind: orphanBucket, name: "default.gone"},
+			{kind: orphanPolicy, name: "default.gone"},
+			{kind: orphanPolicy, name: "default.live.stale"},
+			{kind: orphanUser, name: idleUser},
+			{kind: orphanUser, name: orphanedUser},
+		}))
+	})
+
+	It("should collect the connections of a server together", func() {
+		connections := map[string]minio.Client{
+			"":                               minio.NewFake(),
+			"MinioConnection/default/tenant": minio.NewFake(),
+		}
+		Expect(serverConnections(connections)).To(Equal([][]string{{"", "MinioConnection/default/tenant"}}))
+	})
+
+	It("should parse the gc modes", func() {
+		Expect(ParseGCMode("delete")).To(Equal(GCModeDelete))
+		_, err := ParseGCMode("purge")
+		Expect(err).To(HaveOccurred())
+	})
+})
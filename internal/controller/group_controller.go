@@ -0,0 +1,340 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+const (
+	typeAvailableGroup = "Available"
+	// reasonPolicyNotReady is set while a referenced Policy is missing or not available yet
+	reasonPolicyNotReady = "PolicyNotReady"
+	// reasonConnectionMismatch is set when a referenced Policy lives on another MinIO connection
+	reasonConnectionMismatch = "ConnectionMismatch"
+	finalizerNameGroup       = "group.ixday.github.io/finalizer"
+)
+
+// errPolicyNotReady reports a referenced Policy the group has to wait for.
+type errPolicyNotReady struct {
+	name, reason string
+}
+
+func (e errPolicyNotReady) Error() string {
+	return fmt.Sprintf("policy %s %s", e.name, e.reason)
+}
+
+// errConnectionMismatch reports a referenced Policy hosted on another MinIO server than the group.
+type errConnectionMismatch struct {
+	name string
+}
+
+func (e errConnectionMismatch) Error() string {
+	return fmt.Sprintf("policy %s does not use the connection of the group", e.name)
+}
+
+// GroupReconciler reconciles a Group object
+type GroupReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Clients  *minio.Registry
+	// ResyncInterval, when set, periodically verifies the MinIO state of the available groups
+	ResyncInterval time.Duration
+}
+
+// +kubebuilder:rbac:groups=minio.ixday.github.io,resources=groups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=minio.ixday.github.io,resources=groups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=minio.ixday.github.io,resources=groups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=minio.ixday.github.io,resources=policies,verbs=get;list;watch
+// +kubebuilder:rbac:resources=secrets,verbs=get;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile converges the members of the group and the canned policies attached to it
+// with the Policy resources it references.
+func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	group := &miniov1alpha1.Group{}
+	if err := r.Get(ctx, req.NamespacedName, group); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get group")
+		return ctrl.Result{}, err
+	}
+
+	minioClient, err := r.Clients.Client(group.Spec.ConnectionRef, group.Namespace)
+	if err != nil {
+		// connections are reconciled on their own, wait for the client to be registered
+		// unless the connection was deleted, the resource could never be released otherwise
+		if released, err := releaseWithoutConnection(ctx, r.Client, r.Recorder, group, group.Spec.ConnectionRef, finalizerNameGroup); err != nil {
+			return ctrl.Result{}, err
+		} else if released {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "MinIO connection unavailable")
+		if group.DeletionTimestamp.IsZero() {
+			meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{Type: typeAvailableGroup,
+				Status: metav1.ConditionFalse, Reason: reasonConnectionNotReady,
+				Message: fmt.Sprintf("MinIO connection unavailable: %s", err)})
+			if err := r.Status().Update(ctx, group); err != nil {
+				log.Error(err, "Failed to update group status")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: connectionRetryInterval}, nil
+	}
+
+	if group.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(group, finalizerNameGroup) {
+			controllerutil.AddFinalizer(group, finalizerNameGroup)
+			if err := r.Update(ctx, group); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		if controllerutil.ContainsFinalizer(group, finalizerNameGroup) {
+			log.Info("Deleting group", "Group.Name", group.GroupName())
+			if err := minioClient.GroupDelete(ctx, group.GroupName()); err != nil {
+				log.Error(err, "Failed to delete group", "Group.Name", group.GroupName())
+				r.Recorder.Eventf(group, corev1.EventTypeWarning, eventDeleteFailed,
+					"Failed to delete the group: %s", err)
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(group, corev1.EventTypeNormal, eventGroupDeleted, "Deleted group %s", group.GroupName())
+
+			controllerutil.RemoveFinalizer(group, finalizerNameGroup)
+			if err := r.Update(ctx, group); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if len(group.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{
+			Type: typeAvailableGroup, Status: metav1.ConditionUnknown,
+			Reason: "Reconciling", Message: "Starting reconciliation",
+		})
+		if err := r.Status().Update(ctx, group); err != nil {
+			log.Error(err, "Failed to update group status")
+			return ctrl.Result{}, err
+		}
+		if err := r.Get(ctx, req.NamespacedName, group); err != nil {
+			log.Error(err, "Failed to re-fetch group")
+			return ctrl.Result{}, err
+		}
+	}
+	drift := newDriftReport(group.Status.Conditions, typeAvailableGroup, group.Generation)
+
+	groupMinio, err := r.resolveGroup(ctx, group)
+	var notReady errPolicyNotReady
+	var mismatch errConnectionMismatch
+	if errors.As(err, &mismatch) {
+		// the connections are immutable, the group waits for the reference to be fixed
+		log.Info("Refusing a policy of another connection", "Policy.Name", mismatch.name)
+		r.Recorder.Eventf(group, corev1.EventTypeWarning, eventConnectionMismatch, "%s", err)
+		meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{Type: typeAvailableGroup,
+			Status: metav1.ConditionFalse, Reason: reasonConnectionMismatch, ObservedGeneration: group.Generation,
+			Message: fmt.Sprintf("Policy refused: %s", err)})
+		if err := r.Status().Update(ctx, group); err != nil {
+			log.Error(err, "Failed to update group status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	} else if errors.As(err, &notReady) {
+		// the policies are watched, the group is reconciled again once they are available
+		log.V(2).Info("Waiting for policy", "Policy.Name", notReady.name, "Reason", notReady.reason)
+		meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{Type: typeAvailableGroup,
+			Status: metav1.ConditionFalse, Reason: reasonPolicyNotReady, ObservedGeneration: group.Generation,
+			Message: fmt.Sprintf("Waiting for %s", err)})
+		if err := r.Status().Update(ctx, group); err != nil {
+			log.Error(err, "Failed to update group status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to resolve the policies of the group")
+		return ctrl.Result{}, err
+	}
+
+	changes, err := minioClient.GroupReconcile(ctx, groupMinio)
+	if err != nil {
+		log.Error(err, "Failed to reconcile group", "Group.Name", groupMinio.Name)
+		return ctrl.Result{}, err
+	}
+	recordGroupChanges(r.Recorder, group, groupMinio.Name, changes)
+	drift.add(changes.Created, "group")
+	drift.add(changes.Enabled, "group status")
+	drift.add(changes.MembersUpdated, "group members")
+	drift.add(changes.PoliciesUpdated, "group policies")
+
+	if message := drift.message(); message != "" {
+		r.Recorder.Eventf(group, corev1.EventTypeWarning, eventDriftRepaired,
+			"%s of group %s", message, groupMinio.Name)
+	}
+	drift.setCondition(&group.Status.Conditions, group.Generation)
+	group.Status.Members = groupMinio.Members
+	group.Status.Policies = groupMinio.Policies
+	meta.SetStatusCondition(&group.Status.Conditions, metav1.Condition{Type: typeAvailableGroup,
+		Status: metav1.ConditionTrue, Reason: "Reconciling", ObservedGeneration: group.Generation,
+		Message: fmt.Sprintf("Group %s created successfully", groupMinio.Name)})
+	if err := r.Status().Update(ctx, group); err != nil {
+		log.Error(err, "Failed to update group status")
+		return ctrl.Result{}, err
+	}
+	result := ctrl.Result{}
+	requeueForResync(&result, r.ResyncInterval)
+	return result, nil
+}
+
+// resolveGroup returns the MinIO group matching the resource: the canned policies of the referenced
+// Policy resources and the users generated for them, along with the external users.
+func (r *GroupReconciler) resolveGroup(ctx context.Context, group *miniov1alpha1.Group) (minio.Group, error) {
+	resolved := minio.Group{Name: group.GroupName(), Policies: []string{}, Members: []string{}}
+	for _, name := range group.Spec.Policies {
+		policy, err := r.availablePolicy(ctx, group, name)
+		if err != nil {
+			return resolved, err
+		}
+		resolved.Policies = append(resolved.Policies, policy.PolicyName())
+	}
+	for _, member := range group.Spec.Members {
+		user := member.User
+		if member.PolicyRef != "" {
+			policy, err := r.availablePolicy(ctx, group, member.PolicyRef)
+			if err != nil {
+				return resolved, err
+			}
+			if policy.Spec.Identities != nil {
+				return resolved, errPolicyNotReady{name: policy.Name, reason: "is bound to external identities, it has no user"}
+			}
+			if user, err = r.policyUser(ctx, policy); err != nil {
+				return resolved, err
+			}
+		}
+		if !slices.Contains(resolved.Members, user) {
+			resolved.Members = append(resolved.Members, user)
+		}
+	}
+	return resolved, nil
+}
+
+// availablePolicy returns the Policy once its canned policy and user exist in MinIO, on the
+// connection of the group.
+func (r *GroupReconciler) availablePolicy(
+	ctx context.Context, group *miniov1alpha1.Group, name string,
+) (*miniov1alpha1.Policy, error) {
+	policy := &miniov1alpha1.Policy{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: group.Namespace, Name: name}, policy)
+	if apierrors.IsNotFound(err) {
+		return nil, errPolicyNotReady{name: name, reason: "does not exist"}
+	} else if err != nil {
+		return nil, err
+	}
+	if !policy.DeletionTimestamp.IsZero() {
+		return nil, errPolicyNotReady{name: name, reason: "is being deleted"}
+	}
+	if !meta.IsStatusConditionTrue(policy.Status.Conditions, typeAvailablePolicy) {
+		return nil, errPolicyNotReady{name: name, reason: "is not available"}
+	}
+	ref, err := policyConnectionRef(ctx, r.Client, policy)
+	if err != nil {
+		return nil, err
+	}
+	if connectionKey(ref, policy.Namespace) != connectionKey(group.Spec.ConnectionRef, group.Namespace) {
+		return nil, errConnectionMismatch{name: name}
+	}
+	return policy, nil
+}
+
+// policyUser returns the user generated for the policy, read from its secret.
+func (r *GroupReconciler) policyUser(ctx context.Context, policy *miniov1alpha1.Policy) (string, error) {
+	secretName := policy.Spec.SecretName
+	if secretName == "" {
+		secretName = policy.Name
+	}
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: secretName}, secret)
+	if apierrors.IsNotFound(err) {
+		return "", errPolicyNotReady{name: policy.Name, reason: "has no secret"}
+	} else if err != nil {
+		return "", err
+	}
+	user := string(secret.Data["user"])
+	if user == "" {
+		return "", errPolicyNotReady{name: policy.Name, reason: "has no user"}
+	}
+	return user, nil
+}
+
+// referencesPolicy tells whether the group attaches the policy or has its user as member.
+func referencesPolicy(spec *miniov1alpha1.GroupSpec, name string) bool {
+	if slices.Contains(spec.Policies, name) {
+		return true
+	}
+	return slices.ContainsFunc(spec.Members, func(member miniov1alpha1.GroupMember) bool {
+		return member.PolicyRef == name
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&miniov1alpha1.Group{}).
+		// the groups follow the availability and the user rotations of their policies
+		Watches(&miniov1alpha1.Policy{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, policy client.Object) []ctrl.Request {
+				groups := &miniov1alpha1.GroupList{}
+				if err := r.List(ctx, groups, client.InNamespace(policy.GetNamespace())); err != nil {
+					return nil
+				}
+				requests := []ctrl.Request{}
+				for _, group := range groups.Items {
+					if referencesPolicy(&group.Spec, policy.GetName()) {
+						requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{
+							Name: group.Name, Namespace: group.Namespace,
+						}})
+					}
+				}
+				return requests
+			}),
+		).
+		Named("group").
+		Complete(r)
+}
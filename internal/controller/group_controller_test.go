@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+var _ = Describe("Group Controller", func() {
+	Context("When reconciling a resource", func() {
+		const (
+			resourceName = "test-resource"
+			groupName    = "default.test-resource"
+			policyName   = "default.test-resource.test-resource"
+		)
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		var (
+			fake                 *minio.Fake
+			recorder             *record.FakeRecorder
+			controllerReconciler *GroupReconciler
+			policyReconciler     *PolicyReconciler
+		)
+
+		reconcileTimes := func(times int) {
+			for range times {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+		reconcilePolicy := func() {
+			for range 2 {
+				_, err := policyReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+		getGroup := func() *miniov1alpha1.Group {
+			resource := &miniov1alpha1.Group{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+
+		BeforeEach(func() {
+			fake = minio.NewFake()
+			recorder = record.NewFakeRecorder(100)
+			clients := minio.NewRegistry(fake)
+			controllerReconciler = &GroupReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+				Clients:  clients,
+			}
+			policyReconciler = &PolicyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(100),
+				Clients:  clients,
+			}
+
+			By("creating the custom resources for the Kinds Bucket, Policy and Group")
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec:       miniov1alpha1.BucketSpec{Policy: "private"},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Policy{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: miniov1alpha1.PolicySpec{
+					BucketName: resourceName,
+					Statements: []miniov1alpha1.Statement{{Effect: "Allow", Actions: []string{"s3:ListBucket"}}},
+				},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Group{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: miniov1alpha1.GroupSpec{
+					Policies: []string{resourceName},
+					Members:  []miniov1alpha1.GroupMember{{PolicyRef: resourceName}, {User: "alice"}},
+				},
+			})).To(Succeed())
+
+			By("Resolving the name of the bucket")
+			bucketReconciler := &BucketReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(100),
+				Clients:  clients,
+			}
+			for range 2 {
+				_, err := bucketReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instances Group, Policy and Bucket")
+			for _, resource := range []client.Object{
+				&miniov1alpha1.Group{}, &miniov1alpha1.Policy{}, &miniov1alpha1.Bucket{},
+			} {
+				if err := k8sClient.Get(ctx, typeNamespacedName, resource); err != nil {
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+					continue
+				}
+				resource.SetFinalizers(nil)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+			}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"}}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+		})
+
+		It("should wait for its policies to be available", func() {
+			reconcileTimes(2)
+			_, ok := fake.Group(groupName)
+			Expect(ok).To(BeFalse())
+			condition := meta.FindStatusCondition(getGroup().Status.Conditions, typeAvailableGroup)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(reasonPolicyNotReady))
+		})
+
+		It("should attach the policies and add the members to the group", func() {
+			reconcilePolicy()
+			reconcileTimes(2)
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal " + eventGroupCreated + " ")))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, secret)).To(Succeed())
+			user := string(secret.Data["user"])
+			group, ok := fake.Group(groupName)
+			Expect(ok).To(BeTrue())
+			Expect(group.Members).To(ConsistOf(user, "alice"))
+			Expect(group.Policies).To(ConsistOf(policyName))
+
+			resource := getGroup()
+			Expect(resource.Finalizers).To(ContainElement(finalizerNameGroup))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeAvailableGroup)).To(BeTrue())
+			Expect(resource.Status.Members).To(ConsistOf(user, "alice"))
+			Expect(resource.Status.Policies).To(ConsistOf(policyName))
+		})
+
+		It("should repair a group changed behind the controller", func() {
+			reconcilePolicy()
+			reconcileTimes(2)
+			fake.SetGroup(groupName, minio.FakeGroup{Members: []string{"alice", "mallory"}, Disabled: true})
+
+			reconcileTimes(1)
+			group, _ := fake.Group(groupName)
+			Expect(group.Members).NotTo(ContainElement("mallory"))
+			Expect(group.Policies).To(ConsistOf(policyName))
+			Expect(group.Disabled).To(BeFalse())
+			Expect(meta.IsStatusConditionTrue(getGroup().Status.Conditions, typeDriftDetected)).To(BeTrue())
+		})
+
+		It("should detach a policy being deleted from the group", func() {
+			reconcilePolicy()
+			reconcileTimes(2)
+			policy := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+
+			By("releasing the policy despite the group referencing it")
+			_, err := policyReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, ok := fake.CannedPolicy(policyName)
+			Expect(ok).To(BeFalse())
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, policy))).To(BeTrue())
+			group, _ := fake.Group(groupName)
+			Expect(group.Policies).To(BeEmpty())
+
+			By("waiting for the policy to come back")
+			reconcileTimes(1)
+			condition := meta.FindStatusCondition(getGroup().Status.Conditions, typeAvailableGroup)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(reasonPolicyNotReady))
+		})
+
+		It("should refuse a policy of another connection", func() {
+			reconcilePolicy()
+			ref := &miniov1alpha1.ConnectionReference{Name: "other"}
+			key := minio.ConnectionKey(miniov1alpha1.KindMinioConnection, "default", ref.Name)
+			_, err := controllerReconciler.Clients.Set(key, minio.ClientOptions{Endpoint: "127.0.0.1:1", User: "a", Password: "b"})
+			Expect(err).NotTo(HaveOccurred())
+			other := types.NamespacedName{Name: resourceName + "-other", Namespace: "default"}
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Group{
+				ObjectMeta: metav1.ObjectMeta{Name: other.Name, Namespace: other.Namespace},
+				Spec:       miniov1alpha1.GroupSpec{Policies: []string{resourceName}, ConnectionRef: ref},
+			})).To(Succeed())
+			DeferCleanup(func() {
+				resource := &miniov1alpha1.Group{}
+				Expect(k8sClient.Get(ctx, other, resource)).To(Succeed())
+				resource.SetFinalizers(nil)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+			})
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: other})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			resource := &miniov1alpha1.Group{}
+			Expect(k8sClient.Get(ctx, other, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeAvailableGroup)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(reasonConnectionMismatch))
+			Eventually(recorder.Events).Should(Receive(HavePrefix("Warning " + eventConnectionMismatch + " ")))
+		})
+
+		It("should remove the group on deletion", func() {
+			reconcilePolicy()
+			reconcileTimes(2)
+			Expect(k8sClient.Delete(ctx, getGroup())).To(Succeed())
+
+			reconcileTimes(1)
+			_, ok := fake.Group(groupName)
+			Expect(ok).To(BeFalse())
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &miniov1alpha1.Group{}))).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+// reconcileIdentities grants the canned policy to the external identities of the policy instead of
+// a generated user, neither a Secret nor access keys are managed for it.
+func (r *PolicyReconciler) reconcileIdentities(
+	ctx context.Context, minioClient minio.Client, policy *miniov1alpha1.Policy,
+	policyMinio *minio.Policy, drift *driftReport,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	identities := policy.Spec.Identities
+
+	changes, err := minioClient.IdentityPolicyReconcile(ctx, policyMinio, minio.Identities{
+		LDAPUsers: identities.LDAPUsers, LDAPGroups: identities.LDAPGroups,
+	})
+	if errors.Is(err, minio.ErrPolicyNotManaged) {
+		return r.refuseAdoption(ctx, policy, policyMinio.Name, err)
+	} else if err != nil {
+		log.Error(err, "Failed to bind the policy to its identities", "Policy.Name", policyMinio.Name)
+		return ctrl.Result{}, err
+	}
+	recordPolicyChanges(r.Recorder, policy, policyMinio.Name, changes)
+	drift.addPolicyChanges(changes)
+
+	if message := drift.message(); message != "" {
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventDriftRepaired,
+			"%s of policy %s", message, policyMinio.Name)
+	}
+	drift.setCondition(&policy.Status.Conditions, policy.Generation)
+	policy.Status.Identities = boundIdentities(identities, policyMinio.Name)
+	meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeAvailablePolicy,
+		Status: metav1.ConditionTrue, Reason: "Reconciling", ObservedGeneration: policy.Generation,
+		Message: fmt.Sprintf("Policy %s bound to %d identities", policyMinio.Name, len(policy.Status.Identities))})
+	if err := r.Status().Update(ctx, policy); err != nil {
+		log.Error(err, "Failed to update Policy status")
+		return ctrl.Result{}, err
+	}
+	result := ctrl.Result{}
+	requeueForResync(&result, r.ResyncInterval)
+	return result, nil
+}
+
+// boundIdentities lists the identities granted the canned policy, for the status.
+func boundIdentities(identities *miniov1alpha1.ExternalIdentities, name string) []miniov1alpha1.BoundIdentity {
+	bound := []miniov1alpha1.BoundIdentity{}
+	for _, user := range identities.LDAPUsers {
+		bound = append(bound, miniov1alpha1.BoundIdentity{Type: miniov1alpha1.IdentityLDAPUser, Name: user})
+	}
+	for _, group := range identities.LDAPGroups {
+		bound = append(bound, miniov1alpha1.BoundIdentity{Type: miniov1alpha1.IdentityLDAPGroup, Name: group})
+	}
+	if identities.OIDC {
+		// the OIDC users are granted the policies named in their claim
+		bound = append(bound, miniov1alpha1.BoundIdentity{Type: miniov1alpha1.IdentityOIDCClaim, Name: name})
+	}
+	return bound
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+// collectTimeout bounds the listing of the resources and the usage queries of a scrape
+const collectTimeout = 10 * time.Second
+
+var (
+	orphanResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "minio_controller_orphans",
+		Help: "Number of MinIO entities created by the controller without resource, by connection and kind.",
+	}, []string{"connection", "kind"})
+	orphansDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "minio_controller_orphans_deleted_total",
+		Help: "Orphans removed from MinIO by the garbage collection, by kind.",
+	}, []string{"kind"})
+)
+
+// Collectors returns the metrics updated by the controllers, they are registered
+// once on the metrics registry of the manager.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{orphanResources, orphansDeleted}
+}
+
+var (
+	managedResourcesDesc = prometheus.NewDesc(
+		"minio_controller_managed_resources",
+		"Number of managed resources, by kind and condition status.",
+		[]string{"kind", "condition", "status"}, nil,
+	)
+	bucketUsageBytesDesc = prometheus.NewDesc(
+		"minio_controller_bucket_usage_bytes",
+		"Size of the bucket as computed by the last data usage scan of MinIO.",
+		[]string{"namespace", "name", "bucket"}, nil,
+	)
+	bucketObjectsDesc = prometheus.NewDesc(
+		"minio_controller_bucket_objects",
+		"Number of objects in the bucket as computed by the last data usage scan of MinIO.",
+		[]string{"namespace", "name", "bucket"}, nil,
+	)
+)
+
+// ResourceCollector reports the managed buckets and policies by condition, the values
+// are computed at scrape time from the cache of the manager. The usage of the buckets
+// is queried from MinIO when BucketUsage is set.
+type ResourceCollector struct {
+	Reader      client.Reader
+	Clients     *minio.Registry
+	BucketUsage bool
+}
+
+var _ prometheus.Collector = &ResourceCollector{}
+
+// Describe implements prometheus.Collector.
+func (c *ResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedResourcesDesc
+	if c.BucketUsage {
+		ch <- bucketUsageBytesDesc
+		ch <- bucketObjectsDesc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *ResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+	log := logf.FromContext(ctx).WithName("metrics")
+
+	buckets := &v1alpha1.BucketList{}
+	if err := c.Reader.List(ctx, buckets); err != nil {
+		log.Error(err, "Failed to list buckets")
+	} else {
+		conditions := make([][]metav1.Condition, 0, len(buckets.Items))
+		for _, bucket := range buckets.Items {
+			conditions = append(conditions, bucket.Status.Conditions)
+		}
+		collectConditions(ch, "Bucket", conditions)
+		if c.BucketUsage {
+			c.collectUsage(ctx, ch, buckets.Items)
+		}
+	}
+
+	policies := &v1alpha1.PolicyList{}
+	if err := c.Reader.List(ctx, policies); err != nil {
+		log.Error(err, "Failed to list policies")
+	} else {
+		conditions := make([][]metav1.Condition, 0, len(policies.Items))
+		for _, policy := range policies.Items {
+			conditions = append(conditions, policy.Status.Conditions)
+		}
+		collectConditions(ch, "Policy", conditions)
+	}
+}
+
+// collectConditions counts the resources of a kind by condition type and status.
+func collectConditions(ch chan<- prometheus.Metric, kind string, resources [][]metav1.Condition) {
+	type key struct{ condition, status string }
+	counts := map[key]int{}
+	for _, conditions := range resources {
+		for _, condition := range conditions {
+			counts[key{condition.Type, string(condition.Status)}]++
+		}
+	}
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			managedResourcesDesc, prometheus.GaugeValue, float64(count), kind, k.condition, k.status,
+		)
+	}
+}
+
+// collectUsage reports the usage of the created buckets, the data usage of each
+// connection is queried once per scrape.
+func (c *ResourceCollector) collectUsage(ctx context.Context, ch chan<- prometheus.Metric, buckets []v1alpha1.Bucket) {
+	log := logf.FromContext(ctx).WithName("metrics")
+	usages := map[string]map[string]minio.UsageInfo{}
+	for _, bucket := range buckets {
+		if bucket.Status.BucketName == "" {
+			continue
+		}
+		key := connectionKey(bucket.Spec.ConnectionRef, bucket.Namespace)
+		usage, ok := usages[key]
+		if !ok {
+			minioClient, err := c.Clients.Client(bucket.Spec.ConnectionRef, bucket.Namespace)
+			if err == nil {
+				usage, err = minioClient.BucketsUsage(ctx)
+			}
+			if err != nil {
+				log.Error(err, "Failed to get the bucket usage", "connection", key)
+			}
+			usages[key] = usage
+		}
+		info, ok := usage[bucket.Status.BucketName]
+		if !ok {
+			continue
+		}
+		labels := []string{bucket.Namespace, bucket.Name, bucket.Status.BucketName}
+		ch <- prometheus.MustNewConstMetric(bucketUsageBytesDesc, prometheus.GaugeValue, float64(info.Size), labels...)
+		ch <- prometheus.MustNewConstMetric(bucketObjectsDesc, prometheus.GaugeValue, float64(info.Objects), labels...)
+	}
+}
+
+// connectionKey identifies the connection of a resource, the default one has an empty key.
+func connectionKey(ref *v1alpha1.ConnectionReference, namespace string) string {
+	if ref == nil {
+		return ""
+	}
+	kind := ref.Kind
+	if kind == "" {
+		kind = v1alpha1.KindMinioConnection
+	}
+	return minio.ConnectionKey(kind, namespace, ref.Name)
+}
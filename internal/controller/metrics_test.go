@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+var _ = Describe("Resource collector", func() {
+	ctx := context.Background()
+
+	It("should count the buckets by condition", func() {
+		for _, name := range []string{"metrics-available", "metrics-unavailable"} {
+			bucket := &miniov1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec:       miniov1alpha1.BucketSpec{Policy: "private"},
+			}
+			Expect(k8sClient.Create(ctx, bucket)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, bucket)
+
+			status := metav1.ConditionTrue
+			if name == "metrics-unavailable" {
+				status = metav1.ConditionFalse
+			}
+			meta.SetStatusCondition(&bucket.Status.Conditions, metav1.Condition{
+				Type: typeAvailableBucket, Status: status, Reason: "Testing",
+			})
+			Expect(k8sClient.Status().Update(ctx, bucket)).To(Succeed())
+		}
+
+		collector := &ResourceCollector{Reader: k8sClient, Clients: minio.NewRegistry(minio.NewStub())}
+		expected := `
+# HELP minio_controller_managed_resources Number of managed resources, by kind and condition status.
+# TYPE minio_controller_managed_resources gauge
+minio_controller_managed_resources{condition="Available",kind="Bucket",status="False"} 1
+minio_controller_managed_resources{condition="Available",kind="Bucket",status="True"} 1
+`
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected),
+			"minio_controller_managed_resources")).To(Succeed())
+	})
+})
@@ -0,0 +1,242 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+const (
+	// typeReadyConnection represents the health of the MinIO server behind a connection
+	typeReadyConnection = "Ready"
+	// reasonConnectionNotReady is set on the resources whose connection has no client yet
+	reasonConnectionNotReady = "ConnectionNotReady"
+	// resources waiting for their connection are retried at this interval
+	connectionRetryInterval = 10 * time.Second
+	// connections are health checked at this interval
+	connectionHealthInterval = time.Minute
+	// maximum duration of a health check
+	connectionHealthTimeout = 10 * time.Second
+)
+
+// MinioConnectionReconciler reconciles a MinioConnection object
+type MinioConnectionReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Clients *minio.Registry
+}
+
+// +kubebuilder:rbac:groups=minio.ixday.github.io,resources=minioconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups=minio.ixday.github.io,resources=minioconnections/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile registers the client of the connection and reports the health of the server.
+func (r *MinioConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	key := minio.ConnectionKey(miniov1alpha1.KindMinioConnection, req.Namespace, req.Name)
+	connection := &miniov1alpha1.MinioConnection{}
+	if err := r.Get(ctx, req.NamespacedName, connection); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Clients.Delete(key)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get connection")
+		return ctrl.Result{}, err
+	}
+
+	condition := reconcileConnection(ctx, r.Client, r.Clients, key, &connection.Spec, connection.Namespace)
+	condition.ObservedGeneration = connection.Generation
+	meta.SetStatusCondition(&connection.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, connection); err != nil {
+		log.Error(err, "Failed to update connection status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: connectionHealthInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MinioConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&miniov1alpha1.MinioConnection{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, secret client.Object) []ctrl.Request {
+				connections := &miniov1alpha1.MinioConnectionList{}
+				if err := r.List(ctx, connections, client.InNamespace(secret.GetNamespace())); err != nil {
+					return nil
+				}
+				requests := []ctrl.Request{}
+				for _, connection := range connections.Items {
+					if referencesSecret(&connection.Spec, secret.GetName()) {
+						requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{
+							Name: connection.Name, Namespace: connection.Namespace,
+						}})
+					}
+				}
+				return requests
+			}),
+		).
+		Named("minioconnection").
+		Complete(r)
+}
+
+// connectionGone tells if the referenced connection no longer exists, the default connection never goes away.
+func connectionGone(
+	ctx context.Context, reader client.Reader, ref *miniov1alpha1.ConnectionReference, namespace string,
+) (bool, error) {
+	if ref == nil {
+		return false, nil
+	}
+	var connection client.Object = &miniov1alpha1.MinioConnection{}
+	key := types.NamespacedName{Name: ref.Name, Namespace: namespace}
+	if ref.Kind == miniov1alpha1.KindClusterMinioConnection {
+		connection, key.Namespace = &miniov1alpha1.ClusterMinioConnection{}, ""
+	}
+	err := reader.Get(ctx, key, connection)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
+
+// releaseWithoutConnection removes the finalizer of a resource being deleted once its connection no
+// longer exists, nothing can reach MinIO anymore and the resources in MinIO are left in place. It
+// reports whether the finalizer was removed.
+func releaseWithoutConnection(
+	ctx context.Context, c client.Client, recorder record.EventRecorder, object client.Object,
+	ref *miniov1alpha1.ConnectionReference, finalizer string,
+) (bool, error) {
+	if object.GetDeletionTimestamp().IsZero() || !controllerutil.ContainsFinalizer(object, finalizer) {
+		return false, nil
+	}
+	if gone, err := connectionGone(ctx, c, ref, object.GetNamespace()); err != nil || !gone {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Connection deleted, releasing the resource without cleaning MinIO",
+		"Connection.Name", ref.Name)
+	recorder.Eventf(object, corev1.EventTypeWarning, eventConnectionGone,
+		"Connection %s was deleted, the resources in MinIO are left in place", ref.Name)
+	controllerutil.RemoveFinalizer(object, finalizer)
+	return true, c.Update(ctx, object)
+}
+
+// referencesSecret tells if the connection depends on the secret, namespaces are left to the caller.
+func referencesSecret(spec *miniov1alpha1.MinioConnectionSpec, name string) bool {
+	if spec.CredentialsSecretRef.Name == name {
+		return true
+	}
+	if spec.TLS == nil {
+		return false
+	}
+	return (spec.TLS.CASecretRef != nil && spec.TLS.CASecretRef.Name == name) ||
+		(spec.TLS.ClientCertSecretRef != nil && spec.TLS.ClientCertSecretRef.Name == name)
+}
+
+// reconcileConnection registers the client of the connection, the secrets are read in the given
+// namespace. The returned condition reports whether the server is reachable with the credentials.
+func reconcileConnection(
+	ctx context.Context, reader client.Reader, clients *minio.Registry,
+	key string, spec *miniov1alpha1.MinioConnectionSpec, namespace string,
+) metav1.Condition {
+	log := log.FromContext(ctx)
+
+	options, err := connectionOptions(ctx, reader, spec, namespace)
+	if err != nil {
+		log.Error(err, "Invalid connection secrets")
+		clients.Delete(key)
+		return metav1.Condition{Type: typeReadyConnection, Status: metav1.ConditionFalse,
+			Reason: "InvalidSecret", Message: err.Error()}
+	}
+	minioClient, err := clients.Set(key, options)
+	if err != nil {
+		log.Error(err, "Invalid connection")
+		return metav1.Condition{Type: typeReadyConnection, Status: metav1.ConditionFalse,
+			Reason: "InvalidConfiguration", Message: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, connectionHealthTimeout)
+	defer cancel()
+	if err := minioClient.Health(ctx); err != nil {
+		log.Error(err, "MinIO server unhealthy", "Endpoint", spec.Endpoint)
+		return metav1.Condition{Type: typeReadyConnection, Status: metav1.ConditionFalse,
+			Reason: "Unreachable", Message: err.Error()}
+	}
+	return metav1.Condition{Type: typeReadyConnection, Status: metav1.ConditionTrue,
+		Reason: "Connected", Message: fmt.Sprintf("MinIO server %s is reachable", spec.Endpoint)}
+}
+
+// connectionOptions reads the secrets referenced by the connection in the given namespace.
+func connectionOptions(
+	ctx context.Context, reader client.Reader, spec *miniov1alpha1.MinioConnectionSpec, namespace string,
+) (minio.ClientOptions, error) {
+	options := minio.ClientOptions{Endpoint: spec.Endpoint, Region: spec.Region}
+
+	credentials := &corev1.Secret{}
+	key := types.NamespacedName{Name: spec.CredentialsSecretRef.Name, Namespace: namespace}
+	if err := reader.Get(ctx, key, credentials); err != nil {
+		return options, err
+	}
+	options.User, options.Password = string(credentials.Data["user"]), string(credentials.Data["password"])
+	if options.User == "" || options.Password == "" {
+		return options, fmt.Errorf("secret %s: %w", key, minio.ErrInvalidSecret)
+	}
+
+	if spec.TLS == nil {
+		return options, nil
+	}
+	options.Secure, options.InsecureSkipVerify = true, spec.TLS.InsecureSkipVerify
+	if spec.TLS.CASecretRef != nil {
+		ca := &corev1.Secret{}
+		key := types.NamespacedName{Name: spec.TLS.CASecretRef.Name, Namespace: namespace}
+		if err := reader.Get(ctx, key, ca); err != nil {
+			return options, err
+		}
+		if options.CA = ca.Data["ca.crt"]; options.CA == nil {
+			return options, fmt.Errorf("secret %s has no ca.crt", key)
+		}
+	}
+	if spec.TLS.ClientCertSecretRef != nil {
+		certificate := &corev1.Secret{}
+		key := types.NamespacedName{Name: spec.TLS.ClientCertSecretRef.Name, Namespace: namespace}
+		if err := reader.Get(ctx, key, certificate); err != nil {
+			return options, err
+		}
+		options.Certificate, options.Key = certificate.Data[corev1.TLSCertKey], certificate.Data[corev1.TLSPrivateKeyKey]
+		if options.Certificate == nil || options.Key == nil {
+			return options, fmt.Errorf("secret %s has no tls.crt or tls.key", key)
+		}
+	}
+	return options, nil
+}
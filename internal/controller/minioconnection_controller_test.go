@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+var _ = Describe("MinioConnection Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-connection"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		ref := &miniov1alpha1.ConnectionReference{Name: resourceName}
+
+		BeforeEach(func() {
+			By("creating the credentials and the custom resource for the Kind MinioConnection")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Data:       map[string][]byte{"user": []byte("admin"), "password": []byte("password")},
+			}
+			err := k8sClient.Get(ctx, typeNamespacedName, &corev1.Secret{})
+			if err != nil && errors.IsNotFound(err) {
+				Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			}
+			err = k8sClient.Get(ctx, typeNamespacedName, &miniov1alpha1.MinioConnection{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &miniov1alpha1.MinioConnection{
+					ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+					Spec: miniov1alpha1.MinioConnectionSpec{
+						// nothing listens there, the server is reported unreachable
+						Endpoint:             "127.0.0.1:1",
+						CredentialsSecretRef: miniov1alpha1.SecretReference{Name: resourceName},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &miniov1alpha1.MinioConnection{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+
+			By("Cleanup the specific resource instance MinioConnection")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			})).To(Succeed())
+		})
+
+		It("should register the client and report the health of the server", func() {
+			By("Reconciling the created resource")
+			clients := minio.NewRegistry(nil)
+			controllerReconciler := &MinioConnectionReconciler{
+				Client:  k8sClient,
+				Scheme:  k8sClient.Scheme(),
+				Clients: clients,
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(connectionHealthInterval))
+
+			_, err = clients.Client(ref, "default")
+			Expect(err).NotTo(HaveOccurred())
+
+			connection := &miniov1alpha1.MinioConnection{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, connection)).To(Succeed())
+			condition := meta.FindStatusCondition(connection.Status.Conditions, typeReadyConnection)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("Unreachable"))
+		})
+
+		It("should forget the client once the connection is deleted", func() {
+			By("Reconciling a connection missing from the cluster")
+			clients := minio.NewRegistry(nil)
+			key := minio.ConnectionKey(miniov1alpha1.KindMinioConnection, "other", resourceName)
+			_, err := clients.Set(key, minio.ClientOptions{Endpoint: "127.0.0.1:1", User: "a", Password: "b"})
+			Expect(err).NotTo(HaveOccurred())
+
+			controllerReconciler := &MinioConnectionReconciler{
+				Client:  k8sClient,
+				Scheme:  k8sClient.Scheme(),
+				Clients: clients,
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resourceName, Namespace: "other"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients.Client(ref, "other")).Error().To(MatchError(minio.ErrConnectionNotReady))
+		})
+	})
+})
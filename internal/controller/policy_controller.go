@@ -19,7 +19,9 @@ package controller
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +32,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -47,6 +50,10 @@ const (
 	// typeAvailableBucket represents the status of the Bucket reconciliation
 	typeAvailablePolicy = "Available"
 	typeBucketExists    = "BucketExists"
+	// reasonOutOfBucket is set when the policy document reaches outside of its bucket
+	reasonOutOfBucket = "OutOfBucket"
+	// reasonSecretConflict is set when a secret of the policy belongs to another resource or user
+	reasonSecretConflict = "SecretConflict"
 	// name of our custom finalizer
 	finalizerNamePolicy = "policy.ixday.github.io/finalizer"
 	annotationPolicy    = "policy.ixday.github.io/secret"
@@ -55,14 +62,22 @@ const (
 // PolicyReconciler reconciles a Policy object
 type PolicyReconciler struct {
 	client.Client
-	Scheme      *runtime.Scheme
-	MinioClient minio.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Clients  *minio.Registry
+	// AuthRetry, when set, retries the policies refused by MinIO once the credentials are reloaded
+	AuthRetry *AuthRetry
+	// UnrestrictedNamespaces may use policy documents granting access outside of their bucket
+	UnrestrictedNamespaces []string
+	// ResyncInterval, when set, periodically verifies the MinIO state of the available policies
+	ResyncInterval time.Duration
 }
 
 // +kubebuilder:rbac:groups=minio.ixday.github.io,resources=policies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=minio.ixday.github.io,resources=policies/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=minio.ixday.github.io,resources=policies/finalizers,verbs=update
 // +kubebuilder:rbac:resources=secrets,verbs=get;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -88,6 +103,33 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	ref, err := policyConnectionRef(ctx, r.Client, policy)
+	if err != nil {
+		log.Error(err, "Failed to get associated bucket")
+		return ctrl.Result{}, err
+	}
+	minioClient, err := r.Clients.Client(ref, policy.Namespace)
+	if err != nil {
+		// connections are reconciled on their own, wait for the client to be registered
+		// unless the connection was deleted, the resource could never be released otherwise
+		if released, err := releaseWithoutConnection(ctx, r.Client, r.Recorder, policy, ref, finalizerNamePolicy); err != nil {
+			return ctrl.Result{}, err
+		} else if released {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "MinIO connection unavailable")
+		if policy.DeletionTimestamp.IsZero() {
+			meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeAvailablePolicy,
+				Status: metav1.ConditionFalse, Reason: reasonConnectionNotReady,
+				Message: fmt.Sprintf("MinIO connection unavailable: %s", err)})
+			if err := r.Status().Update(ctx, policy); err != nil {
+				log.Error(err, "Failed to update policy status")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: connectionRetryInterval}, nil
+	}
+
 	// https://book.kubebuilder.io/reference/using-finalizers
 	// examine DeletionTimestamp to determine if object is under deletion
 	if policy.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -105,10 +147,19 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		if controllerutil.ContainsFinalizer(policy, finalizerNamePolicy) {
 			// our finalizer is present, so lets handle any external dependency
 			log.Info("Deleting associated users", "Policy.Name", policy.PolicyName())
-			if err := r.MinioClient.PolicyDelete(ctx, policy.PolicyName()); err != nil {
+			user, err := r.secretUser(ctx, policy)
+			if err != nil {
+				log.Error(err, "Failed to get Secret")
+				return ctrl.Result{}, err
+			}
+			if err := minioClient.PolicyDelete(ctx, policy.PolicyName(), user); err != nil {
 				log.Error(err, "Failed deleting associated users and policies", "Policy.Name", policy.PolicyName())
+				r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventDeleteFailed,
+					"Failed to delete the policy and its users: %s", err)
 				return ctrl.Result{}, err
 			}
+			r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventPolicyDeleted,
+				"Deleted policy %s and its users", policy.PolicyName())
 
 			// remove our finalizer from the list and update it.
 			controllerutil.RemoveFinalizer(policy, finalizerNamePolicy)
@@ -143,12 +194,15 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			return ctrl.Result{}, err
 		}
 	}
+	drift := newDriftReport(policy.Status.Conditions, typeAvailablePolicy, policy.Generation)
 
 	// Retrieve associated bucket
 	bucket := &miniov1alpha1.Bucket{}
 	bucketKey := types.NamespacedName{Namespace: req.Namespace, Name: policy.Spec.BucketName}
 	if err := r.Get(ctx, bucketKey, bucket); err != nil {
 		if apierrors.IsNotFound(err) {
+			r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventBucketNotFound,
+				"Bucket %s does not exist", policy.Spec.BucketName)
 			condition := metav1.Condition{
 				Type:    typeBucketExists,
 				Status:  metav1.ConditionFalse,
@@ -163,6 +217,40 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		log.Error(err, "Failed to get associated bucket")
 		return ctrl.Result{}, err
 	}
+	if bucket.Status.BucketName == "" {
+		// the name of the bucket in MinIO is resolved by the bucket controller
+		log.V(2).Info("Waiting for the bucket name to be resolved", "Bucket.Name", bucket.Name)
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	log.V(2).Info("Reconciling policy")
+	policyMinio := &minio.Policy{
+		Bucket: bucket.BucketName(), Name: policy.PolicyName(),
+	}
+	if policy.Spec.Document != "" {
+		err = policyMinio.SetDocument(policy.Spec.Document,
+			slices.Contains(r.UnrestrictedNamespaces, policy.Namespace))
+	} else {
+		err = policyMinio.SetPolicy(policy.Spec.Statements)
+	}
+	if errors.Is(err, minio.ErrOutOfBucket) {
+		log.Error(err, "policy document out of the bucket", "Policy.Name", policy.PolicyName())
+		r.Recorder.Event(policy, corev1.EventTypeWarning, eventPolicyOutOfBucket, err.Error())
+		meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeAvailablePolicy,
+			Status: metav1.ConditionFalse, Reason: reasonOutOfBucket,
+			Message: fmt.Sprintf("Policy document refused: %s", err)})
+		if err := r.Status().Update(ctx, policy); err != nil {
+			log.Error(err, "Failed to update Policy status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "invalid policy", "Policy.Name", policy.PolicyName())
+		return ctrl.Result{}, err
+	}
+	if policy.Spec.Identities != nil {
+		return r.reconcileIdentities(ctx, minioClient, policy, policyMinio, drift)
+	}
 
 	secret, err := r.getSecret(ctx, policy)
 	if apierrors.IsNotFound(err) {
@@ -190,6 +278,7 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			log.Error(err, "Failed to create new Secret", "Secret.Name", secret.Name)
 			return ctrl.Result{}, err
 		}
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventSecretCreated, "Generated credentials in secret %s", secret.Name)
 		// Secret created successfully
 		// We will requeue the reconciliation so that we can ensure the state
 		// and move forward for the next operations
@@ -210,38 +299,178 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 	}
 
-	log.V(2).Info("Reconciling policy")
-	policyMinio := &minio.Policy{
-		Bucket: bucket.BucketName(), Name: policy.PolicyName(),
+	if changed, err := switchCredentialMode(policy.Spec.CredentialMode, secret); err != nil {
+		log.Error(err, "Failed to switch the credential mode", "Secret.Name", secret.Name)
+		return ctrl.Result{}, err
+	} else if changed {
+		log.Info("Switching the credential mode", "Secret.Name", secret.Name, "Mode", policy.Spec.CredentialMode)
+		if err := r.Update(ctx, secret); err != nil {
+			log.Error(err, "Failed to update Secret", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		}
 	}
-	if err := policyMinio.SetUser(secret.Data["user"], secret.Data["password"]); err != nil {
-		log.Error(err, "invalid credentials", "Secret.Name", secret.Name)
+
+	now := time.Now()
+	if rotated, err := rotateStaticCredentials(policy.Spec.CredentialMode, policy.Annotations,
+		policy.Spec.CredentialRotation, &policy.Status.Credentials, secret, now); err != nil {
+		log.Error(err, "Failed to generate new credentials", "Secret.Name", secret.Name)
 		return ctrl.Result{}, err
+	} else if rotated {
+		log.Info("Rotating credentials", "Secret.Name", secret.Name)
+		if err := r.Update(ctx, secret); err != nil {
+			log.Error(err, "Failed to update Secret with the new credentials", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventCredentialsRotated,
+			"Rotated the credentials of secret %s", secret.Name)
+		// the user replaced by the rotation is not a drift
+		drift.tracking = false
+		// recorded right away, a failure below must not trigger another rotation
+		if err := r.Status().Update(ctx, policy); err != nil {
+			log.Error(err, "Failed to update Policy status")
+			return ctrl.Result{}, err
+		}
 	}
-	if err = policyMinio.SetPolicy(policy.Spec.Statements); err != nil {
-		log.Error(err, "invalid policy", "Policy.Name", policy.PolicyName())
+
+	password := userPassword(policy.Spec.CredentialMode, secret)
+	if err := policyMinio.SetUser(secret.Data["user"], password); err != nil {
+		log.Error(err, "invalid credentials", "Secret.Name", secret.Name)
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventInvalidCredentials,
+			"Secret %s: %s", secret.Name, err)
 		return ctrl.Result{}, err
 	}
-	if err := r.MinioClient.PolicyReconcile(ctx, policyMinio); err != nil {
+	policyChanges, err := minioClient.PolicyReconcile(ctx, policyMinio)
+	if errors.Is(err, minio.ErrPolicyNotManaged) {
+		return r.refuseAdoption(ctx, policy, policyMinio.Name, err)
+	} else if err != nil {
 		log.Error(err, "failed to create user, policy and attach")
 		return ctrl.Result{}, err
 	}
+	recordPolicyChanges(r.Recorder, policy, policyMinio.Name, policyChanges)
+	drift.addPolicyChanges(policyChanges)
+	if policy.Spec.CredentialMode == miniov1alpha1.CredentialModeSTS {
+		userReplaced := policyChanges.PolicyCreated || policyChanges.UserCreated || policyChanges.UserRecreated
+		if refreshed, err := refreshSTSCredentials(ctx, minioClient, policy.Annotations, policy.Spec.STS,
+			&policy.Status.Credentials, secret, userReplaced, now); err != nil {
+			log.Error(err, "Failed to issue temporary credentials", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		} else if refreshed {
+			log.Info("Refreshing temporary credentials", "Secret.Name", secret.Name)
+			if err := r.Update(ctx, secret); err != nil {
+				log.Error(err, "Failed to update Secret with the temporary credentials", "Secret.Name", secret.Name)
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventCredentialsRefreshed,
+				"Issued temporary credentials in secret %s, valid until %s",
+				secret.Name, policy.Status.Credentials.ExpirationTime.UTC().Format(time.RFC3339))
+		}
+	}
+	if changed, err := reconcileOverlap(ctx, minioClient, policy.Status.Credentials, secret, now); errors.Is(err,
+		minio.ErrServiceAccountNotOwned) {
+		return r.refuseSecret(ctx, policy, err)
+	} else if err != nil {
+		log.Error(err, "Failed to reconcile the previous credentials", "Secret.Name", secret.Name)
+		return ctrl.Result{}, err
+	} else if changed {
+		log.Info("Removing the expired previous credentials", "Secret.Name", secret.Name)
+		if err := r.Update(ctx, secret); err != nil {
+			log.Error(err, "Failed to update Secret", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventPreviousCredsExpired,
+			"Removed the previous credentials of secret %s", secret.Name)
+	}
+	if changed, err := renderSecret(policy.Spec.SecretTemplate,
+		secretData(minioClient, bucket.BucketName(), secret), secret); errors.Is(err, minio.ErrInvalidSecretTemplate) {
+		log.Error(err, "invalid secret template", "Secret.Name", secret.Name)
+		r.Recorder.Event(policy, corev1.EventTypeWarning, eventInvalidSecretTemplate, err.Error())
+		meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeAvailablePolicy,
+			Status: metav1.ConditionFalse, Reason: reasonInvalidSecretTemplate,
+			Message: fmt.Sprintf("Secret template refused: %s", err)})
+		if err := r.Status().Update(ctx, policy); err != nil {
+			log.Error(err, "Failed to update Policy status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to render secret template", "Secret.Name", secret.Name)
+		return ctrl.Result{}, err
+	} else if changed {
+		log.Info("Rendering secret template", "Secret.Name", secret.Name)
+		if err := r.Update(ctx, secret); err != nil {
+			log.Error(err, "Failed to update Secret", "Secret.Name", secret.Name)
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.reconcileAccessKeys(ctx, minioClient, policy,
+		bucket.BucketName(), string(secret.Data["user"]), drift, now); errors.Is(err, errSecretNotControlled) ||
+		errors.Is(err, minio.ErrServiceAccountNotOwned) {
+		return r.refuseSecret(ctx, policy, err)
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// The following implementation will update the status
+	if message := drift.message(); message != "" {
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventDriftRepaired,
+			"%s of policy %s", message, policyMinio.Name)
+	}
+	drift.setCondition(&policy.Status.Conditions, policy.Generation)
 	meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeAvailablePolicy,
-		Status: metav1.ConditionTrue, Reason: "Reconciling",
+		Status: metav1.ConditionTrue, Reason: "Reconciling", ObservedGeneration: policy.Generation,
 		Message: fmt.Sprintf("Policy %s created successfully", policy.PolicyName())})
 
 	if err := r.Status().Update(ctx, policy); err != nil {
 		log.Error(err, "Failed to update Policy status")
 		return ctrl.Result{}, err
 	}
+	result := ctrl.Result{}
+	requeueForCredentials(&result, policy.Status.Credentials, now)
+	requeueForAccessKeys(&result, policy.Spec.AccessKeys, policy.Status.AccessKeys, now)
+	requeueForResync(&result, r.ResyncInterval)
+	return result, nil
+}
+
+// refuseAdoption reports a canned policy of the same name which was not created by the controller,
+// it is left untouched until it is removed from MinIO.
+func (r *PolicyReconciler) refuseAdoption(
+	ctx context.Context, policy *miniov1alpha1.Policy, name string, err error,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	log.Info("Refusing to manage existing canned policy", "Policy.Name", name)
+	r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventAdoptionRefused,
+		"Canned policy %s can not be managed: %s", name, err)
+	meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeAvailablePolicy,
+		Status: metav1.ConditionFalse, Reason: "AdoptionRefused",
+		Message: fmt.Sprintf("Canned policy %s can not be managed: %s", name, err)})
+	if err := r.Status().Update(ctx, policy); err != nil {
+		log.Error(err, "Failed to update Policy status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// refuseSecret reports a secret of the policy which belongs to another resource or names the access key
+// of another user, it is left untouched.
+func (r *PolicyReconciler) refuseSecret(
+	ctx context.Context, policy *miniov1alpha1.Policy, err error,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	log.Info("Refusing to use a Secret the Policy does not control", "reason", err.Error())
+	r.Recorder.Eventf(policy, corev1.EventTypeWarning, eventSecretConflict, "%s", err)
+	meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeAvailablePolicy,
+		Status: metav1.ConditionFalse, Reason: reasonSecretConflict, ObservedGeneration: policy.Generation,
+		Message: fmt.Sprintf("Secret refused: %s", err)})
+	if err := r.Status().Update(ctx, policy); err != nil {
+		log.Error(err, "Failed to update Policy status")
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *PolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&miniov1alpha1.Policy{}).
 		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(
 			func(ctx context.Context, cm client.Object) []ctrl.Request {
@@ -275,8 +504,33 @@ func (r *PolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				},
 			}),
 		).
-		Named("policy").
-		Complete(r)
+		// a deleted access key secret is regenerated
+		Owns(&corev1.Secret{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc:  func(event.CreateEvent) bool { return false },
+			UpdateFunc:  func(event.UpdateEvent) bool { return false },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		})).
+		Named("policy")
+	if r.AuthRetry == nil {
+		return b.Complete(r)
+	}
+	return b.WatchesRawSource(r.AuthRetry.Source()).Complete(r.AuthRetry.Wrap(r))
+}
+
+// policyConnectionRef returns the connection of the policy, inherited from its bucket when unset.
+func policyConnectionRef(
+	ctx context.Context, reader client.Reader, policy *miniov1alpha1.Policy,
+) (*miniov1alpha1.ConnectionReference, error) {
+	if policy.Spec.ConnectionRef != nil {
+		return policy.Spec.ConnectionRef, nil
+	}
+	bucket := &miniov1alpha1.Bucket{}
+	err := reader.Get(ctx, types.NamespacedName{Name: policy.Spec.BucketName, Namespace: policy.Namespace}, bucket)
+	if apierrors.IsNotFound(err) {
+		// reported later on, the default connection is only used to clean up
+		return nil, nil
+	}
+	return bucket.Spec.ConnectionRef, err
 }
 
 func (r *PolicyReconciler) getSecret(
@@ -292,8 +546,21 @@ func (r *PolicyReconciler) getSecret(
 	return secret, err
 }
 
+// secretUser returns the MinIO user of the secret of the policy, it is empty when the policy grants
+// identities or when the secret is gone.
+func (r *PolicyReconciler) secretUser(ctx context.Context, policy *miniov1alpha1.Policy) (string, error) {
+	if policy.Spec.Identities != nil {
+		return "", nil
+	}
+	secret, err := r.getSecret(ctx, policy)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	return string(secret.Data["user"]), err
+}
+
 func (r *PolicyReconciler) secretForPolicy(policy *miniov1alpha1.Policy) (*corev1.Secret, error) {
-	user, err := minio.GenerateAccessKey(0, nil)
+	user, err := minio.GenerateUser()
 	if err != nil {
 		return nil, err
 	}
@@ -315,6 +582,10 @@ func (r *PolicyReconciler) secretForPolicy(policy *miniov1alpha1.Policy) (*corev
 		secret.Name = policy.Spec.SecretName
 	}
 
+	// the password is moved out of the credentials in STS mode
+	if _, err := switchCredentialMode(policy.Spec.CredentialMode, secret); err != nil {
+		return nil, err
+	}
 	// Set the ownerRef for the Deployment
 	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/owners-dependents/
 	if err := ctrl.SetControllerReference(policy, secret, r.Scheme); err != nil {
@@ -18,11 +18,16 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"k8s.io/apimachinery/pkg/api/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,78 +38,382 @@ import (
 
 var _ = Describe("Policy Controller", func() {
 	Context("When reconciling a resource", func() {
-		var err error
-		const resourceName = "test-resource"
+		const (
+			resourceName = "test-resource"
+			policyName   = "default.test-resource.test-resource"
+		)
 
 		ctx := context.Background()
 
 		typeNamespacedName := types.NamespacedName{
 			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+			Namespace: "default",
+		}
+		var (
+			fake                 *minio.Fake
+			recorder             *record.FakeRecorder
+			controllerReconciler *PolicyReconciler
+		)
+
+		reconcileTimes := func(times int) {
+			for range times {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+		getSecret := func() *corev1.Secret {
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, secret)).To(Succeed())
+			return secret
 		}
-		policy := &miniov1alpha1.Policy{}
-		bucket := &miniov1alpha1.Bucket{}
 
 		BeforeEach(func() {
-			By("creating the custom resource for the Kind Bucket")
-			err = k8sClient.Get(ctx, typeNamespacedName, bucket)
-			if err != nil && errors.IsNotFound(err) {
-				resource := &miniov1alpha1.Bucket{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      resourceName,
-						Namespace: "default",
-					},
-					Spec: miniov1alpha1.BucketSpec{
-						Policy: "private",
-					},
-				}
-				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			fake = minio.NewFake()
+			recorder = record.NewFakeRecorder(100)
+			clients := minio.NewRegistry(fake)
+			controllerReconciler = &PolicyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+				Clients:  clients,
 			}
+
+			By("creating the custom resource for the Kind Bucket")
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: miniov1alpha1.BucketSpec{
+					Policy: "private",
+				},
+			})).To(Succeed())
 			By("creating the custom resource for the Kind Policy")
-			err = k8sClient.Get(ctx, typeNamespacedName, policy)
-			if err != nil && errors.IsNotFound(err) {
-				resource := &miniov1alpha1.Policy{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      resourceName,
-						Namespace: "default",
-					},
-					Spec: miniov1alpha1.PolicySpec{
-						BucketName: resourceName,
-						Statements: []miniov1alpha1.Statement{{
-							Effect: "Allow",
-							Actions: []string{
-								"s3:ListBucket", "s3:GetBucketLocation",
-							},
-						}},
-					},
-				}
-				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Create(ctx, &miniov1alpha1.Policy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: miniov1alpha1.PolicySpec{
+					BucketName: resourceName,
+					Statements: []miniov1alpha1.Statement{{
+						Effect: "Allow",
+						Actions: []string{
+							"s3:ListBucket", "s3:GetBucketLocation",
+						},
+					}},
+				},
+			})).To(Succeed())
+
+			By("Resolving the name of the bucket")
+			bucketReconciler := &BucketReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(100),
+				Clients:  clients,
+			}
+			for range 2 {
+				_, err := bucketReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
 			}
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
+			By("Cleanup the specific resource instances Policy and Bucket")
+			for _, resource := range []client.Object{&miniov1alpha1.Policy{}, &miniov1alpha1.Bucket{}} {
+				if err := k8sClient.Get(ctx, typeNamespacedName, resource); err != nil {
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+					continue
+				}
+				resource.SetFinalizers(nil)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+			}
+			for _, name := range []string{resourceName, resourceName + "-ci"} {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			}
+		})
+
+		It("should create the policy and attach it to a generated user", func() {
+			reconcileTimes(2)
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal " + eventSecretCreated + " ")))
+
+			document, ok := fake.CannedPolicy(policyName)
+			Expect(ok).To(BeTrue())
+			Expect(string(document)).To(ContainSubstring("s3:ListBucket"))
+
+			secret := getSecret()
+			user, ok := fake.User(string(secret.Data["user"]))
+			Expect(ok).To(BeTrue())
+			Expect(user.Password).To(Equal(string(secret.Data["password"])))
+			Expect(user.Policies).To(ConsistOf(policyName))
+
 			resource := &miniov1alpha1.Policy{}
-			err := k8sClient.Get(ctx, typeNamespacedName, resource)
-			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(finalizerNamePolicy))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeAvailablePolicy)).To(BeTrue())
+		})
 
-			By("Cleanup the specific resource instance Policy")
+		It("should update the document when the statements change", func() {
+			reconcileTimes(2)
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Statements[0].Actions = append(resource.Spec.Statements[0].Actions, "s3:GetObject")
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			reconcileTimes(1)
+			document, _ := fake.CannedPolicy(policyName)
+			Expect(string(document)).To(ContainSubstring("s3:GetObject"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			// a change of the resource is not a drift
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, typeDriftDetected)).To(BeNil())
+		})
+
+		It("should refuse to take over a canned policy created outside of the controller", func() {
+			document := []byte(`{"Version":"2012-10-17","Statement":[]}`)
+			fake.SetCannedPolicy(policyName, document)
+
+			reconcileTimes(2)
+			current, _ := fake.CannedPolicy(policyName)
+			Expect(current).To(Equal(document))
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			available := meta.FindStatusCondition(resource.Status.Conditions, typeAvailablePolicy)
+			Expect(available).NotTo(BeNil())
+			Expect(available.Reason).To(Equal("AdoptionRefused"))
+			Eventually(recorder.Events).Should(Receive(HavePrefix("Warning " + eventAdoptionRefused + " ")))
+
+			By("leaving the canned policy in place on deletion")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			reconcileTimes(1)
+			_, ok := fake.CannedPolicy(policyName)
+			Expect(ok).To(BeTrue())
 		})
-		It("should successfully reconcile the resource", func() {
-			By("Reconciling the created resource")
-			controllerReconciler := &PolicyReconciler{
-				Client:      k8sClient,
-				Scheme:      k8sClient.Scheme(),
-				MinioClient: minio.NewStub(),
+
+		It("should restore a policy removed behind the controller", func() {
+			reconcileTimes(2)
+			fake.RemoveCannedPolicy(policyName)
+
+			reconcileTimes(1)
+			_, ok := fake.CannedPolicy(policyName)
+			Expect(ok).To(BeTrue())
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeDriftDetected)).To(BeTrue())
+		})
+
+		It("should replace the user with overlapping credentials on rotation", func() {
+			reconcileTimes(2)
+			previous := getSecret()
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.CredentialRotation = &miniov1alpha1.CredentialRotation{
+				Interval: metav1.Duration{Duration: 24 * time.Hour},
+				Overlap:  &metav1.Duration{Duration: time.Hour},
 			}
+			resource.Annotations = map[string]string{miniov1alpha1.AnnotationRotateCredentials: "now"}
+			resource.Spec.AccessKeys = []miniov1alpha1.AccessKey{{Name: "ci"}}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			reconcileTimes(1)
+			secret := getSecret()
+			Expect(secret.Data["user"]).NotTo(Equal(previous.Data["user"]))
+			Expect(secret.Data[keyPreviousUser]).To(Equal(previous.Data["user"]))
+			Expect(fake.Users()).To(ConsistOf(string(secret.Data["user"])))
+			// the previous access key keeps working until the overlap expires
+			account, ok := fake.ServiceAccount(string(previous.Data["user"]))
+			Expect(ok).To(BeTrue())
+			Expect(account.Parent).To(Equal(string(secret.Data["user"])))
+			// the access keys removed with the previous user are recreated under the new one
+			key := &corev1.Secret{}
+			keyName := types.NamespacedName{Name: resourceName + "-ci", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, keyName, key)).To(Succeed())
+			account, ok = fake.ServiceAccount(string(key.Data["user"]))
+			Expect(ok).To(BeTrue())
+			Expect(account.Parent).To(Equal(string(secret.Data["user"])))
+		})
+
+		It("should refuse an access key secret it does not control", func() {
+			keyName := types.NamespacedName{Name: resourceName + "-ci", Namespace: "default"}
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: keyName.Name, Namespace: keyName.Namespace},
+				Data:       map[string][]byte{"user": []byte("FOREIGNACCESSKEY"), "password": []byte("secret")},
+			})).To(Succeed())
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.AccessKeys = []miniov1alpha1.AccessKey{{Name: "ci"}}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			reconcileTimes(2)
+			_, ok := fake.ServiceAccount("FOREIGNACCESSKEY")
+			Expect(ok).To(BeFalse())
+			key := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, keyName, key)).To(Succeed())
+			Expect(key.Data["user"]).To(Equal([]byte("FOREIGNACCESSKEY")))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			available := meta.FindStatusCondition(resource.Status.Conditions, typeAvailablePolicy)
+			Expect(available).NotTo(BeNil())
+			Expect(available.Reason).To(Equal(reasonSecretConflict))
+			Eventually(recorder.Events).Should(Receive(HavePrefix("Warning " + eventSecretConflict + " ")))
+		})
+
+		It("should refuse previous credentials held by the access key of another user", func() {
+			reconcileTimes(2)
+			foreign := minio.NewDefaultPolicy("foreign")
+			Expect(foreign.SetUser([]byte("mallory"), []byte("password"))).To(Succeed())
+			_, err := fake.PolicyReconcile(ctx, foreign)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = fake.ServiceAccountReconcile(ctx, minio.ServiceAccount{Parent: "mallory", AccessKey: "FOREIGNACCESSKEY"})
+			Expect(err).NotTo(HaveOccurred())
+			secret := getSecret()
+			secret.Data[keyPreviousUser] = []byte("FOREIGNACCESSKEY")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			reconcileTimes(1)
+			account, ok := fake.ServiceAccount("FOREIGNACCESSKEY")
+			Expect(ok).To(BeTrue())
+			Expect(account.Parent).To(Equal("mallory"))
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			available := meta.FindStatusCondition(resource.Status.Conditions, typeAvailablePolicy)
+			Expect(available).NotTo(BeNil())
+			Expect(available.Reason).To(Equal(reasonSecretConflict))
+			Eventually(recorder.Events).Should(Receive(HavePrefix("Warning " + eventSecretConflict + " ")))
+		})
+
+		It("should manage access keys as service accounts of the user", func() {
+			reconcileTimes(2)
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.AccessKeys = []miniov1alpha1.AccessKey{{Name: "ci"}}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			By("creating the key in its own secret")
+			reconcileTimes(1)
+			key := &corev1.Secret{}
+			keyName := types.NamespacedName{Name: resourceName + "-ci", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, keyName, key)).To(Succeed())
+			account, ok := fake.ServiceAccount(string(key.Data["user"]))
+			Expect(ok).To(BeTrue())
+			Expect(account.Parent).To(Equal(string(getSecret().Data["user"])))
+			Expect(account.SecretKey).To(Equal(string(key.Data["password"])))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccessKeys).To(HaveLen(1))
+			Expect(resource.Status.AccessKeys[0].AccessKeyID).To(Equal(string(key.Data["user"])))
+			Expect(resource.Status.AccessKeys[0].State).To(Equal(miniov1alpha1.AccessKeyEnabled))
+			Expect(resource.Status.AccessKeys[0].LastRotationTime).NotTo(BeNil())
+
+			By("disabling the key")
+			resource.Spec.AccessKeys[0].Disabled = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			reconcileTimes(1)
+			account, _ = fake.ServiceAccount(string(key.Data["user"]))
+			Expect(account.Disabled).To(BeTrue())
+
+			By("revoking the key removed from the spec")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.AccessKeys = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			reconcileTimes(1)
+			_, ok = fake.ServiceAccount(string(key.Data["user"]))
+			Expect(ok).To(BeFalse())
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, keyName, key))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccessKeys).To(BeEmpty())
+		})
 
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
-				NamespacedName: typeNamespacedName,
+		It("should replace the static credentials by temporary ones in STS mode", func() {
+			reconcileTimes(2)
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.CredentialMode = miniov1alpha1.CredentialModeSTS
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			previous := getSecret()
+			reconcileTimes(1)
+			secret := getSecret()
+			Expect(secret.Data).NotTo(HaveKey("password"))
+			// the password of the user is reset by the refresh, it is never stored
+			user, _ := fake.User(string(secret.Data["user"]))
+			Expect(user.Password).NotTo(Equal(string(previous.Data["password"])))
+			reconcileTimes(1)
+			current, _ := fake.User(string(secret.Data["user"]))
+			Expect(current).To(Equal(user))
+			session, ok := fake.Session(string(secret.Data[keyAccessKey]))
+			Expect(ok).To(BeTrue())
+			Expect(session.Parent).To(Equal(string(secret.Data["user"])))
+			Expect(string(secret.Data[keySecretKey])).To(Equal(session.SecretKey))
+			Expect(string(secret.Data[keySessionToken])).To(Equal(session.SessionToken))
+			Eventually(recorder.Events).Should(Receive(HavePrefix("Normal " + eventCredentialsRefreshed + " ")))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Credentials.ExpirationTime).NotTo(BeNil())
+			Expect(resource.Status.Credentials.NextRotationTime.Time).To(
+				BeTemporally("<", resource.Status.Credentials.ExpirationTime.Time))
+		})
+
+		It("should bind external identities instead of generating a user", func() {
+			identitiesName := types.NamespacedName{Name: "test-identities", Namespace: "default"}
+			resource := &miniov1alpha1.Policy{
+				ObjectMeta: metav1.ObjectMeta{Name: identitiesName.Name, Namespace: "default"},
+				Spec: miniov1alpha1.PolicySpec{
+					BucketName: resourceName,
+					Statements: []miniov1alpha1.Statement{{Effect: "Allow", Actions: []string{"s3:ListBucket"}}},
+					Identities: &miniov1alpha1.ExternalIdentities{
+						LDAPGroups: []string{"cn=readers,ou=groups,dc=example,dc=org"}, OIDC: true,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				if err := k8sClient.Get(ctx, identitiesName, resource); apierrors.IsNotFound(err) {
+					return
+				}
+				resource.SetFinalizers(nil)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
 			})
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: identitiesName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			name := "default.test-resource.test-identities"
+			_, ok := fake.CannedPolicy(name)
+			Expect(ok).To(BeTrue())
+			Expect(fake.Users()).To(BeEmpty())
+			Expect(fake.LDAPBindings(name).LDAPGroups).To(ConsistOf("cn=readers,ou=groups,dc=example,dc=org"))
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, identitiesName, &corev1.Secret{}))).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, identitiesName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeAvailablePolicy)).To(BeTrue())
+			Expect(resource.Status.Identities).To(ConsistOf(
+				miniov1alpha1.BoundIdentity{
+					Type: miniov1alpha1.IdentityLDAPGroup, Name: "cn=readers,ou=groups,dc=example,dc=org",
+				},
+				miniov1alpha1.BoundIdentity{Type: miniov1alpha1.IdentityOIDCClaim, Name: name},
+			))
+
+			By("releasing the identities on deletion")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: identitiesName})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			Expect(fake.LDAPBindings(name).LDAPGroups).To(BeEmpty())
+		})
+
+		It("should remove the policy and its user before releasing the finalizer", func() {
+			reconcileTimes(2)
+			resource := &miniov1alpha1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			reconcileTimes(1)
+			_, ok := fake.CannedPolicy(policyName)
+			Expect(ok).To(BeFalse())
+			Expect(fake.Users()).To(BeEmpty())
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		})
 	})
 })
@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+const (
+	// keys of the secret holding the credentials replaced by the last rotation, during the overlap
+	keyPreviousUser     = "previousUser"
+	keyPreviousPassword = "previousPassword"
+)
+
+// rotateCredentials regenerates the credentials of the secret when the rotation is due or forced
+// by the annotation, it reports whether the secret was rotated. The secret and the status are
+// updated in place and left to the caller to persist.
+func rotateCredentials(
+	annotations map[string]string, rotation *miniov1alpha1.CredentialRotation,
+	status **miniov1alpha1.CredentialsStatus, secret *corev1.Secret, now time.Time,
+) (bool, error) {
+	request := annotations[miniov1alpha1.AnnotationRotateCredentials]
+	if rotation == nil && request == "" {
+		if *status != nil {
+			(*status).NextRotationTime = nil
+		}
+		return false, nil
+	}
+	if *status == nil {
+		*status = &miniov1alpha1.CredentialsStatus{}
+	}
+	credentials := *status
+	if credentials.LastRotationTime == nil {
+		// generated before the rotation was enabled, they are as old as the secret
+		created := secret.CreationTimestamp
+		credentials.LastRotationTime = &created
+	}
+	credentials.NextRotationTime = nil
+	if rotation != nil {
+		next := metav1.NewTime(credentials.LastRotationTime.Add(rotation.Interval.Duration))
+		credentials.NextRotationTime = &next
+	}
+	forced := request != "" && request != credentials.RotationRequest
+	if !forced && (credentials.NextRotationTime == nil || now.Before(credentials.NextRotationTime.Time)) {
+		return false, nil
+	}
+
+	password, err := minio.GenerateSecretKey(0, nil)
+	if err != nil {
+		return false, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if rotation != nil && rotation.Overlap != nil {
+		// the previous access key must be freed for the service account
+		user, err := minio.GenerateUser()
+		if err != nil {
+			return false, err
+		}
+		secret.Data[keyPreviousUser], secret.Data[keyPreviousPassword] = secret.Data["user"], secret.Data["password"]
+		secret.Data["user"] = user
+		expiration := metav1.NewTime(now.Add(rotation.Overlap.Duration))
+		credentials.PreviousExpirationTime = &expiration
+	}
+	secret.Data["password"] = password
+
+	last := metav1.NewTime(now)
+	credentials.LastRotationTime, credentials.RotationRequest = &last, request
+	credentials.NextRotationTime = nil
+	if rotation != nil {
+		next := metav1.NewTime(now.Add(rotation.Interval.Duration))
+		credentials.NextRotationTime = &next
+	}
+	return true, nil
+}
+
+// reconcileOverlap keeps the previous credentials of the secret working until the end of the
+// overlap through a service account of the new user, then forgets them. It reports whether
+// the secret was changed and must be persisted. A previous user held by a service account of
+// another user is refused with minio.ErrServiceAccountNotOwned.
+func reconcileOverlap(
+	ctx context.Context, minioClient minio.Client,
+	status *miniov1alpha1.CredentialsStatus, secret *corev1.Secret, now time.Time,
+) (bool, error) {
+	previous := string(secret.Data[keyPreviousUser])
+	if previous == "" {
+		return false, nil
+	}
+	if status != nil && status.PreviousExpirationTime != nil && now.Before(status.PreviousExpirationTime.Time) {
+		expiration := status.PreviousExpirationTime.Time
+		_, err := minioClient.ServiceAccountReconcile(ctx, minio.ServiceAccount{
+			Parent: string(secret.Data["user"]), AccessKey: previous,
+			SecretKey: string(secret.Data[keyPreviousPassword]), Expiration: &expiration,
+		})
+		return false, err
+	}
+	// MinIO disables the expired account on its own, removing it keeps the user clean
+	if err := minioClient.ServiceAccountDelete(ctx, previous, string(secret.Data["user"])); err != nil {
+		return false, err
+	}
+	delete(secret.Data, keyPreviousUser)
+	delete(secret.Data, keyPreviousPassword)
+	if status != nil {
+		status.PreviousExpirationTime = nil
+	}
+	return true, nil
+}
+
+// requeueForCredentials shortens the result so the next rotation or the end of the overlap is not missed.
+func requeueForCredentials(result *ctrl.Result, status *miniov1alpha1.CredentialsStatus, now time.Time) {
+	if status == nil {
+		return
+	}
+	for _, deadline := range []*metav1.Time{status.NextRotationTime, status.PreviousExpirationTime} {
+		if deadline != nil {
+			requeueBefore(result, deadline.Time, now)
+		}
+	}
+}
+
+// requeueBefore shortens the result so the deadline is not missed.
+func requeueBefore(result *ctrl.Result, deadline, now time.Time) {
+	after := max(deadline.Sub(now), time.Second)
+	if result.RequeueAfter == 0 || after < result.RequeueAfter {
+		result.RequeueAfter = after
+	}
+}
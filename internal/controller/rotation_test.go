@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+var _ = Describe("Credential rotation", func() {
+	var (
+		now      time.Time
+		secret   *corev1.Secret
+		rotation *miniov1alpha1.CredentialRotation
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))},
+			Data:       map[string][]byte{"user": []byte("user"), "password": []byte("password")},
+		}
+		rotation = &miniov1alpha1.CredentialRotation{Interval: metav1.Duration{Duration: time.Hour}}
+	})
+
+	It("should rotate the password once the interval elapsed", func() {
+		var status *miniov1alpha1.CredentialsStatus
+		rotated, err := rotateCredentials(nil, rotation, &status, secret, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotated).To(BeTrue())
+		Expect(secret.Data["user"]).To(Equal([]byte("user")))
+		Expect(secret.Data["password"]).NotTo(Equal([]byte("password")))
+		Expect(status.LastRotationTime.Time).To(BeTemporally("==", now))
+		Expect(status.NextRotationTime.Time).To(BeTemporally("==", now.Add(time.Hour)))
+
+		rotated, err = rotateCredentials(nil, rotation, &status, secret, now.Add(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotated).To(BeFalse())
+	})
+
+	It("should rotate when forced by the annotation only once per value", func() {
+		annotations := map[string]string{miniov1alpha1.AnnotationRotateCredentials: "1"}
+		var status *miniov1alpha1.CredentialsStatus
+		rotated, err := rotateCredentials(annotations, nil, &status, secret, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotated).To(BeTrue())
+		Expect(status.RotationRequest).To(Equal("1"))
+		Expect(status.NextRotationTime).To(BeNil())
+
+		rotated, err = rotateCredentials(annotations, nil, &status, secret, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotated).To(BeFalse())
+	})
+
+	It("should keep the previous credentials during the overlap", func() {
+		rotation.Overlap = &metav1.Duration{Duration: 10 * time.Minute}
+		var status *miniov1alpha1.CredentialsStatus
+		rotated, err := rotateCredentials(nil, rotation, &status, secret, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotated).To(BeTrue())
+		Expect(secret.Data["user"]).NotTo(Equal([]byte("user")))
+		Expect(secret.Data[keyPreviousUser]).To(Equal([]byte("user")))
+		Expect(secret.Data[keyPreviousPassword]).To(Equal([]byte("password")))
+		Expect(status.PreviousExpirationTime.Time).To(BeTemporally("==", now.Add(10*time.Minute)))
+
+		changed, err := reconcileOverlap(context.Background(), minio.NewStub(), status, secret, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		result := ctrl.Result{RequeueAfter: quotaRefreshInterval}
+		requeueForCredentials(&result, status, now)
+		Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+
+		changed, err = reconcileOverlap(context.Background(), minio.NewStub(), status, secret, now.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(secret.Data).NotTo(HaveKey(keyPreviousUser))
+		Expect(status.PreviousExpirationTime).To(BeNil())
+	})
+})
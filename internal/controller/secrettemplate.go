@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+const (
+	// annotationTemplateKeys lists the keys rendered from the secret template,
+	// they are removed from the secret once dropped from the template
+	annotationTemplateKeys = "minio.ixday.github.io/template-keys"
+	// reasonInvalidSecretTemplate is set when the secret template can not be rendered
+	reasonInvalidSecretTemplate = "InvalidSecretTemplate"
+)
+
+// secretData gathers the placeholders of the secret templates, the temporary credentials
+// of the STS mode take the place of the user and its password.
+func secretData(minioClient minio.Client, bucket string, secret *corev1.Secret) minio.SecretData {
+	endpoint := minioClient.Endpoint()
+	data := minio.SecretData{
+		Endpoint: endpoint.URL, Host: endpoint.Host, Secure: endpoint.Secure, Region: endpoint.Region,
+		Bucket: bucket, User: string(secret.Data["user"]), Password: string(secret.Data["password"]),
+	}
+	if token, ok := secret.Data[keySessionToken]; ok {
+		data.User, data.Password = string(secret.Data[keyAccessKey]), string(secret.Data[keySecretKey])
+		data.SessionToken = string(token)
+	}
+	return data
+}
+
+// renderSecret renders the template into the secret and removes the keys previously rendered
+// which are no longer part of it, it reports whether the secret changed.
+func renderSecret(spec *miniov1alpha1.SecretTemplate, data minio.SecretData, secret *corev1.Secret) (bool, error) {
+	templates, err := minio.NewSecretTemplate(spec)
+	if err != nil {
+		return false, err
+	}
+	rendered, err := templates.Render(data)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for _, key := range strings.Split(secret.Annotations[annotationTemplateKeys], ",") {
+		if _, ok := rendered[key]; !ok && secret.Data[key] != nil {
+			delete(secret.Data, key)
+			changed = true
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for key, value := range rendered {
+		if !bytes.Equal(secret.Data[key], value) {
+			secret.Data[key] = value
+			changed = true
+		}
+	}
+
+	keys := strings.Join(slices.Sorted(maps.Keys(rendered)), ",")
+	if secret.Annotations[annotationTemplateKeys] == keys {
+		return changed, nil
+	}
+	if keys == "" {
+		delete(secret.Annotations, annotationTemplateKeys)
+	} else if secret.Annotations == nil {
+		secret.Annotations = map[string]string{annotationTemplateKeys: keys}
+	} else {
+		secret.Annotations[annotationTemplateKeys] = keys
+	}
+	return true, nil
+}
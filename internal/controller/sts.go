@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+const (
+	// keys of the temporary credentials in the secret of the STS mode
+	keyAccessKey    = "accessKey"
+	keySecretKey    = "secretKey"
+	keySessionToken = "sessionToken"
+	keyExpiration   = "expiration"
+
+	// defaultSTSDuration is the validity of the temporary credentials when unset
+	defaultSTSDuration = time.Hour
+)
+
+// switchCredentialMode converts the secret to the credential mode: the password is removed in STS
+// mode, the one of the user is reset by each AssumeRole and never stored, and the temporary
+// credentials are replaced by a generated password in Static mode. It reports whether the secret changed.
+func switchCredentialMode(mode miniov1alpha1.CredentialMode, secret *corev1.Secret) (bool, error) {
+	if mode == miniov1alpha1.CredentialModeSTS {
+		_, ok := secret.Data["password"]
+		delete(secret.Data, "password")
+		return ok, nil
+	}
+	changed := false
+	for _, key := range []string{keyAccessKey, keySecretKey, keySessionToken, keyExpiration} {
+		if _, ok := secret.Data[key]; ok {
+			delete(secret.Data, key)
+			changed = true
+		}
+	}
+	if _, ok := secret.Data["password"]; ok {
+		return changed, nil
+	}
+	password, err := minio.GenerateSecretKey(0, nil)
+	if err != nil {
+		return false, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data["password"] = password
+	return true, nil
+}
+
+// userPassword returns the password of the MinIO user of the secret, it is empty in STS mode
+// so that the password reset by AssumeRole is left untouched.
+func userPassword(mode miniov1alpha1.CredentialMode, secret *corev1.Secret) []byte {
+	if mode != miniov1alpha1.CredentialModeSTS {
+		return secret.Data["password"]
+	}
+	return []byte{}
+}
+
+// rotateStaticCredentials rotates the credentials of the secret in Static mode, the temporary
+// credentials of the STS mode are refreshed once the user is reconciled instead.
+func rotateStaticCredentials(
+	mode miniov1alpha1.CredentialMode, annotations map[string]string, rotation *miniov1alpha1.CredentialRotation,
+	status **miniov1alpha1.CredentialsStatus, secret *corev1.Secret, now time.Time,
+) (bool, error) {
+	if mode == miniov1alpha1.CredentialModeSTS {
+		return false, nil
+	}
+	if *status != nil {
+		(*status).ExpirationTime = nil
+	}
+	return rotateCredentials(annotations, rotation, status, secret, now)
+}
+
+// refreshSTSCredentials issues temporary credentials into the secret when they are missing, when two
+// thirds of their validity elapsed, when the user was replaced or when a refresh is requested by the
+// rotate-credentials annotation. It reports whether the secret was refreshed, the status is updated
+// in place and both are left to the caller to persist.
+func refreshSTSCredentials(
+	ctx context.Context, minioClient minio.Client, annotations map[string]string, sts *miniov1alpha1.STSCredentials,
+	status **miniov1alpha1.CredentialsStatus, secret *corev1.Secret, userReplaced bool, now time.Time,
+) (bool, error) {
+	if *status == nil {
+		*status = &miniov1alpha1.CredentialsStatus{}
+	}
+	credentials := *status
+	request := annotations[miniov1alpha1.AnnotationRotateCredentials]
+	forced := userReplaced || (request != "" && request != credentials.RotationRequest)
+	if !forced && len(secret.Data[keySessionToken]) > 0 &&
+		credentials.NextRotationTime != nil && now.Before(credentials.NextRotationTime.Time) {
+		return false, nil
+	}
+
+	duration := defaultSTSDuration
+	if sts != nil && sts.Duration.Duration > 0 {
+		duration = sts.Duration.Duration
+	}
+	issued, err := minioClient.AssumeRole(ctx, string(secret.Data["user"]), duration)
+	if err != nil {
+		return false, err
+	}
+	secret.Data[keyAccessKey] = []byte(issued.AccessKey)
+	secret.Data[keySecretKey] = []byte(issued.SecretKey)
+	secret.Data[keySessionToken] = []byte(issued.SessionToken)
+	secret.Data[keyExpiration] = []byte(issued.Expiration.UTC().Format(time.RFC3339))
+
+	last, expiration := metav1.NewTime(now), metav1.NewTime(issued.Expiration)
+	next := metav1.NewTime(now.Add(issued.Expiration.Sub(now) * 2 / 3))
+	credentials.LastRotationTime, credentials.NextRotationTime = &last, &next
+	credentials.ExpirationTime, credentials.RotationRequest = &expiration, request
+	return true, nil
+}
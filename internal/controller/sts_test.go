@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+var _ = Describe("STS credentials", func() {
+	var (
+		ctx    context.Context
+		now    time.Time
+		fake   *minio.Fake
+		secret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		ctx, now, fake = context.Background(), time.Now(), minio.NewFake()
+		secret = &corev1.Secret{Data: map[string][]byte{"user": []byte("AKIAUSER0123456789AB")}}
+		_, err := switchCredentialMode(miniov1alpha1.CredentialModeSTS, secret)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// reconcileUser converges the user as the reconcilers do before a refresh
+	reconcileUser := func() {
+		policy := minio.NewDefaultPolicy("default.data")
+		Expect(policy.SetUser(secret.Data["user"], userPassword(miniov1alpha1.CredentialModeSTS, secret))).To(Succeed())
+		_, err := fake.PolicyReconcile(ctx, policy)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should only reset the password of the user to issue credentials", func() {
+		reconcileUser()
+		user, _ := fake.User(string(secret.Data["user"]))
+		reconcileUser()
+		current, _ := fake.User(string(secret.Data["user"]))
+		Expect(current).To(Equal(user))
+
+		var status *miniov1alpha1.CredentialsStatus
+		_, err := refreshSTSCredentials(ctx, fake, nil, nil, &status, secret, false, now)
+		Expect(err).NotTo(HaveOccurred())
+		reset, _ := fake.User(string(secret.Data["user"]))
+		Expect(reset.Password).NotTo(Equal(user.Password))
+		Expect(reset.Password).NotTo(BeEmpty())
+		for _, value := range secret.Data {
+			Expect(value).NotTo(Equal([]byte(reset.Password)), "the password is never stored")
+		}
+	})
+
+	It("should switch the secret between the credential modes", func() {
+		secret.Data["password"] = []byte("password")
+		changed, err := switchCredentialMode(miniov1alpha1.CredentialModeSTS, secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(secret.Data).NotTo(HaveKey("password"))
+		changed, err = switchCredentialMode(miniov1alpha1.CredentialModeSTS, secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		secret.Data[keySessionToken] = []byte("token")
+		secret.Data[keyAccessKey] = []byte("temporary")
+		changed, err = switchCredentialMode(miniov1alpha1.CredentialModeStatic, secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(secret.Data["password"]).NotTo(BeEmpty())
+		Expect(secret.Data).NotTo(HaveKey(keySessionToken))
+		Expect(secret.Data).NotTo(HaveKey(keyAccessKey))
+
+		changed, err = switchCredentialMode(miniov1alpha1.CredentialModeStatic, secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("should refresh the credentials once two thirds of their validity elapsed", func() {
+		reconcileUser()
+		var status *miniov1alpha1.CredentialsStatus
+		refreshed, err := refreshSTSCredentials(ctx, fake, nil, nil, &status, secret, false, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refreshed).To(BeTrue())
+		session, ok := fake.Session(string(secret.Data[keyAccessKey]))
+		Expect(ok).To(BeTrue())
+		Expect(session.Parent).To(Equal(string(secret.Data["user"])))
+		Expect(string(secret.Data[keySessionToken])).To(Equal(session.SessionToken))
+		Expect(status.ExpirationTime.Time).To(BeTemporally("~", now.Add(time.Hour), time.Second))
+		Expect(status.NextRotationTime.Time).To(BeTemporally("~", now.Add(40*time.Minute), time.Second))
+
+		reconcileUser()
+		refreshed, err = refreshSTSCredentials(ctx, fake, nil, nil, &status, secret, false,
+			now.Add(30*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refreshed).To(BeFalse())
+
+		refreshed, err = refreshSTSCredentials(ctx, fake, nil, nil, &status, secret, false,
+			now.Add(45*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refreshed).To(BeTrue())
+	})
+
+	It("should refresh when requested or when the user was replaced", func() {
+		reconcileUser()
+		sts := &miniov1alpha1.STSCredentials{}
+		sts.Duration.Duration = 2 * time.Hour
+		var status *miniov1alpha1.CredentialsStatus
+		_, err := refreshSTSCredentials(ctx, fake, nil, sts, &status, secret, false, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.ExpirationTime.Time).To(BeTemporally("~", now.Add(2*time.Hour), time.Second))
+
+		annotations := map[string]string{miniov1alpha1.AnnotationRotateCredentials: "1"}
+		refreshed, err := refreshSTSCredentials(ctx, fake, annotations, sts, &status, secret, false, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refreshed).To(BeTrue())
+		refreshed, err = refreshSTSCredentials(ctx, fake, annotations, sts, &status, secret, false, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refreshed).To(BeFalse())
+
+		refreshed, err = refreshSTSCredentials(ctx, fake, annotations, sts, &status, secret, true, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refreshed).To(BeTrue())
+	})
+
+	It("should render the temporary credentials in the templates", func() {
+		secret.Data[keyAccessKey] = []byte("temporary")
+		secret.Data[keySecretKey] = []byte("secret")
+		secret.Data[keySessionToken] = []byte("token")
+		data := secretData(fake, "default.data", secret)
+		Expect(data.User).To(Equal("temporary"))
+		Expect(data.Password).To(Equal("secret"))
+		Expect(data.SessionToken).To(Equal("token"))
+	})
+})
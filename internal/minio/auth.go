@@ -0,0 +1,34 @@
+package minio
+
+import (
+	"errors"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7"
+)
+
+// error codes returned by MinIO when it refuses the credentials of the request
+var authErrorCodes = map[string]struct{}{
+	"InvalidAccessKeyId":    empty,
+	"SignatureDoesNotMatch": empty,
+	"ExpiredToken":          empty,
+	"InvalidToken":          empty,
+}
+
+// IsAuthError tells if MinIO refused the credentials of the client, such
+// failures are expected to be fixed by reloading the credentials.
+func IsAuthError(err error) bool {
+	var (
+		s3Err    minio.ErrorResponse
+		adminErr madmin.ErrorResponse
+		code     string
+	)
+	switch {
+	case errors.As(err, &s3Err):
+		code = s3Err.Code
+	case errors.As(err, &adminErr):
+		code = adminErr.Code
+	}
+	_, ok := authErrorCodes[code]
+	return ok
+}
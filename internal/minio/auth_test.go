@@ -0,0 +1,33 @@
+package minio
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+var authErrorEntries = []struct {
+	name     string
+	err      error
+	expected bool
+}{
+	{name: "nil", err: nil, expected: false},
+	{name: "s3", err: minio.ErrorResponse{Code: "InvalidAccessKeyId"}, expected: true},
+	{name: "admin", err: madmin.ErrorResponse{Code: "SignatureDoesNotMatch"}, expected: true},
+	{name: "wrapped", err: fmt.Errorf("bucket: %w", minio.ErrorResponse{Code: "ExpiredToken"}), expected: true},
+	{name: "joined", err: errors.Join(errors.New("other"), madmin.ErrorResponse{Code: "InvalidAccessKeyId"}), expected: true},
+	{name: "denied", err: minio.ErrorResponse{Code: "AccessDenied"}, expected: false},
+	{name: "unrelated", err: ErrBucketNotEmpty, expected: false},
+}
+
+func TestIsAuthError(t *testing.T) {
+	for _, entry := range authErrorEntries {
+		t.Run(entry.name, func(t *testing.T) {
+			assert.Equal(t, entry.expected, IsAuthError(entry.err))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package minio
+
+import (
+	"context"
+	"errors"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+const (
+	errNoSuchTagSet = "NoSuchTagSet"
+	errNoSuchBucket = "NoSuchBucket"
+	// ClaimTag is the bucket tag recording which resource manages the bucket.
+	ClaimTag = "minio.ixday.github.io/owner"
+)
+
+var (
+	ErrBucketClaimed    = errors.New("bucket is already claimed by another resource")
+	ErrBucketNotAdopted = errors.New("bucket already exists and adoption was not requested")
+)
+
+// BucketClaim records owner as the manager of the bucket through a bucket tag.
+// It returns ErrBucketClaimed when the bucket is managed by another owner and
+// ErrBucketNotAdopted when the bucket is not managed at all and adopt is false.
+// The returned boolean is true when the claim has been written, nothing is written
+// when adopt is false and a missing bucket is never claimed.
+func (c *client) BucketClaim(ctx context.Context, name, owner string, adopt bool) (bool, error) {
+	current, err := c.bucketTags(ctx, name)
+	if minio.ToErrorResponse(err).Code == errNoSuchBucket {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if claim, err := decideClaim(current.ToMap(), owner, adopt); err != nil || !claim {
+		return false, err
+	}
+	if err := current.Set(ClaimTag, owner); err != nil {
+		return false, err
+	}
+	return true, c.SetBucketTagging(ctx, name, current)
+}
+
+// BucketCheckClaim returns the errors of BucketClaim without writing any claim, a missing bucket
+// is always accepted.
+func (c *client) BucketCheckClaim(ctx context.Context, name, owner string, adopt bool) error {
+	current, err := c.bucketTags(ctx, name)
+	if minio.ToErrorResponse(err).Code == errNoSuchBucket {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = decideClaim(current.ToMap(), owner, adopt)
+	return err
+}
+
+// BucketRelease removes the claim of owner on the bucket, so that it can be adopted again.
+func (c *client) BucketRelease(ctx context.Context, name, owner string) error {
+	current, err := c.bucketTags(ctx, name)
+	if minio.ToErrorResponse(err).Code == errNoSuchBucket {
+		return nil
+	}
+	if err != nil || current.ToMap()[ClaimTag] != owner {
+		return err
+	}
+	current.Remove(ClaimTag)
+	if current.Count() == 0 {
+		return c.RemoveBucketTagging(ctx, name)
+	}
+	return c.SetBucketTagging(ctx, name, current)
+}
+
+func (c *client) bucketTags(ctx context.Context, name string) (*tags.Tags, error) {
+	current, err := c.GetBucketTagging(ctx, name)
+	if minio.ToErrorResponse(err).Code == errNoSuchTagSet {
+		return tags.NewTags(nil, false)
+	}
+	return current, err
+}
+
+func decideClaim(current map[string]string, owner string, adopt bool) (bool, error) {
+	switch claimed := current[ClaimTag]; {
+	case claimed == owner:
+		return false, nil
+	case claimed != "":
+		return false, ErrBucketClaimed
+	case !adopt:
+		return false, ErrBucketNotAdopted
+	}
+	return true, nil
+}
@@ -0,0 +1,28 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var decideClaimEntries = []struct {
+	current     map[string]string
+	adopt       bool
+	expected    bool
+	expectedErr error
+}{
+	{map[string]string{}, true, true, nil},
+	{map[string]string{"team": "data"}, false, false, ErrBucketNotAdopted},
+	{map[string]string{ClaimTag: "default/logs"}, false, false, nil},
+	{map[string]string{ClaimTag: "default/logs"}, true, false, nil},
+	{map[string]string{ClaimTag: "other/logs"}, true, false, ErrBucketClaimed},
+}
+
+func Test_decideClaim(t *testing.T) {
+	for _, entry := range decideClaimEntries {
+		got, err := decideClaim(entry.current, "default/logs", entry.adopt)
+		assert.ErrorIs(t, err, entry.expectedErr)
+		assert.Equal(t, entry.expected, got)
+	}
+}
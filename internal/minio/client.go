@@ -1,63 +1,128 @@
 package minio
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/IxDay/api/v1alpha1"
 	"github.com/minio/madmin-go/v3"
 	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/pkg/v3/policy"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
-const defaultLocation = "us-east-1"
+const (
+	defaultLocation = "us-east-1"
+
+	errNoSuchPolicy         = "XMinioAdminNoSuchPolicy"
+	errPolicyAlreadyApplied = "XMinioAdminPolicyChangeAlreadyApplied"
+)
 
 type BucketClient = minio.Client
 type BucketPolicy = v1alpha1.BucketPolicy
+type BucketVersioning = v1alpha1.BucketVersioning
+type VersioningStatus = v1alpha1.VersioningStatus
 
 type Client interface {
-	BucketCreate(ctx context.Context, name string) error
+	BucketCreate(ctx context.Context, name string, objectLocking bool) error
 	BucketDelete(ctx context.Context, name string) error
+	BucketPurge(ctx context.Context, name string, limit int) (int, error)
+	BucketClaim(ctx context.Context, name, owner string, adopt bool) (bool, error)
+	BucketCheckClaim(ctx context.Context, name, owner string, adopt bool) error
+	BucketRelease(ctx context.Context, name, owner string) error
 	BucketExists(ctx context.Context, name string) (bool, error)
 	BucketPolicyReconcile(ctx context.Context, name string, policy BucketPolicy) (bool, error)
-	PolicyReconcile(ctx context.Context, policy *Policy) error
-	PolicyDelete(ctx context.Context, name string) error
+	BucketVersioningReconcile(ctx context.Context, name string, versioning *BucketVersioning) (VersioningStatus, bool, error)
+	BucketLifecycleReconcile(ctx context.Context, name string, lifecycle *BucketLifecycle) (bool, error)
+	BucketQuotaReconcile(ctx context.Context, name string, size uint64) (bool, error)
+	BucketUsage(ctx context.Context, name string) (uint64, error)
+	BucketsUsage(ctx context.Context) (map[string]UsageInfo, error)
+	BucketObjectLockReconcile(ctx context.Context, name string, lock *BucketObjectLock) (bool, error)
+	BucketEncryptionReconcile(ctx context.Context, name string, encryption *BucketEncryption) (bool, error)
+	PolicyReconcile(ctx context.Context, policy *Policy) (PolicyChanges, error)
+	PolicyDelete(ctx context.Context, name, user string) error
+	IdentityPolicyReconcile(ctx context.Context, policy *Policy, identities Identities) (PolicyChanges, error)
+	ServiceAccountReconcile(ctx context.Context, account ServiceAccount) (bool, error)
+	ServiceAccountDelete(ctx context.Context, accessKey, parent string) error
+	UserDelete(ctx context.Context, name string) error
+	CannedPolicyDelete(ctx context.Context, name string) error
+	GroupReconcile(ctx context.Context, group Group) (GroupChanges, error)
+	GroupDelete(ctx context.Context, name string) error
+	AssumeRole(ctx context.Context, user string, duration time.Duration) (TemporaryCredentials, error)
+	Inventory(ctx context.Context) (Inventory, error)
+	Health(ctx context.Context) error
+	Endpoint() Endpoint
 }
 
 type client struct {
 	*minio.Client
 	*madmin.AdminClient
+	region string
+	// transport is shared with the STS requests, nil for the default one
+	transport http.RoundTripper
+	usage     *usageCache
 }
 
 func NewClient(endpoint, user, password string) (Client, error) {
-	creds := credentials.NewStaticV4(user, password, "")
-	minioClient, err := minio.New(endpoint, &minio.Options{Creds: creds})
-	if err != nil {
-		return nil, err
-	}
-	minioAdminClient, err := madmin.NewWithOptions(endpoint, &madmin.Options{Creds: creds})
-	if err != nil {
-		return nil, err
-	}
-	return &client{Client: minioClient, AdminClient: minioAdminClient}, nil
+	return NewClientWithOptions(ClientOptions{Endpoint: endpoint, User: user, Password: password})
 }
 
 var ErrInvalidSecret = errors.New("invalid secret format")
 
 func NewClientFromSecret(secret *corev1.Secret) (Client, error) {
-	var (
-		endpoint = string(secret.Data["endpoint"])
-		user     = string(secret.Data["user"])
-		password = string(secret.Data["password"])
-	)
-	if endpoint == "" || user == "" || password == "" {
-		return nil, ErrInvalidSecret
+	options, err := OptionsFromSecret(secret)
+	if err != nil {
+		return nil, err
 	}
-	return NewClient(endpoint, user, password)
+	return NewClientWithOptions(options)
+}
+
+// OptionsFromSecret reads the endpoint and the credentials of the connection secret.
+// An endpoint prefixed with https:// enables TLS, the optional ca.crt, tls.crt, tls.key
+// and insecureSkipVerify keys configure the verification and the client certificate.
+func OptionsFromSecret(secret *corev1.Secret) (ClientOptions, error) {
+	options := ClientOptions{
+		Endpoint:    string(secret.Data["endpoint"]),
+		User:        string(secret.Data["user"]),
+		Password:    string(secret.Data["password"]),
+		CA:          secret.Data["ca.crt"],
+		Certificate: secret.Data["tls.crt"],
+		Key:         secret.Data["tls.key"],
+	}
+	if scheme, host, found := strings.Cut(options.Endpoint, "://"); found {
+		options.secureSet = true
+		switch scheme {
+		case "https":
+			options.Secure = true
+		case "http":
+		default:
+			return ClientOptions{}, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidSecret, scheme)
+		}
+		options.Endpoint = strings.TrimSuffix(host, "/")
+	}
+	if options.Endpoint == "" || options.User == "" || options.Password == "" {
+		return ClientOptions{}, ErrInvalidSecret
+	}
+	if (options.Certificate == nil) != (options.Key == nil) {
+		return ClientOptions{}, fmt.Errorf("%w: tls.crt and tls.key go together", ErrInvalidSecret)
+	}
+	if skip, ok := secret.Data["insecureSkipVerify"]; ok {
+		options.insecureSkipVerifySet = true
+		var err error
+		if options.InsecureSkipVerify, err = strconv.ParseBool(string(skip)); err != nil {
+			return ClientOptions{}, fmt.Errorf("%w: insecureSkipVerify: %w", ErrInvalidSecret, err)
+		}
+	}
+	return options, nil
 }
 
 func NewMinioClientFromSecret(secret *corev1.Secret) (*minio.Client, error) {
@@ -68,8 +133,8 @@ func NewMinioClientFromSecret(secret *corev1.Secret) (*minio.Client, error) {
 	return c.(*client).Client, nil
 }
 
-func (c *client) BucketCreate(ctx context.Context, name string) error {
-	opts := minio.MakeBucketOptions{Region: defaultLocation}
+func (c *client) BucketCreate(ctx context.Context, name string, objectLocking bool) error {
+	opts := minio.MakeBucketOptions{Region: c.region, ObjectLocking: objectLocking}
 
 	if err := c.MakeBucket(ctx, name, opts); err != nil {
 		// Check to see if we already own this bucket (which happens if you run this twice)
@@ -88,16 +153,125 @@ func (c *client) BucketExists(ctx context.Context, name string) (bool, error) {
 	return c.Client.BucketExists(ctx, name)
 }
 
+var ErrBucketNotEmpty = errors.New("bucket is not empty")
+
+// BucketDelete removes the bucket, it succeeds if the bucket is already gone and
+// returns ErrBucketNotEmpty when objects are still stored in it.
 func (c *client) BucketDelete(ctx context.Context, name string) error {
-	return c.RemoveBucket(ctx, name)
+	err := c.RemoveBucket(ctx, name)
+	switch minio.ToErrorResponse(err).Code {
+	case errNoSuchBucket:
+		return nil
+	case "BucketNotEmpty":
+		return ErrBucketNotEmpty
+	}
+	return err
+}
+
+// MinIO refuses to remove an object version under compliance retention or legal hold with
+// an invalid request describing it as WORM protected
+const (
+	errObjectLocked        = "InvalidRequest"
+	errObjectLockedMessage = "WORM protected"
+)
+
+// ErrObjectsRetained is returned by BucketPurge when object versions are protected by a compliance
+// retention or a legal hold, they can only be removed once the protection is lifted.
+var ErrObjectsRetained = errors.New("object versions are protected by a retention or a legal hold")
+
+// isRetained tells if the removal of an object version failed because of its retention.
+func isRetained(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	return resp.Code == errObjectLocked && strings.Contains(resp.Message, errObjectLockedMessage)
+}
+
+// BucketPurge removes at most limit object versions, delete markers included, from the bucket
+// and returns how many were removed, even along an error. Governance retention is bypassed, the
+// versions still retained are skipped so the listing goes on past them, and ErrObjectsRetained is
+// joined to the error when some were found. A purge only ends when zero is returned.
+func (c *client) BucketPurge(ctx context.Context, name string, limit int) (int, error) {
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	removed, retained, errs := 0, false, []error{}
+	batch := make([]minio.ObjectInfo, 0, limit)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		batchRemoved, batchRetained, batchErrs := c.removeVersions(ctx, name, batch)
+		removed, retained, errs = removed+batchRemoved, retained || batchRetained, append(errs, batchErrs...)
+		batch = batch[:0]
+	}
+	opts := minio.ListObjectsOptions{Recursive: true, WithVersions: true}
+	for object := range c.ListObjects(listCtx, name, opts) {
+		if object.Err != nil {
+			// remove what was listed so far, the next purge resumes the listing
+			errs = append(errs, object.Err)
+			break
+		}
+		if batch = append(batch, object); len(batch) < limit-removed {
+			continue
+		}
+		if flush(); removed == limit || len(errs) > 0 {
+			break
+		}
+	}
+	cancel()
+	flush()
+
+	if retained {
+		errs = append(errs, ErrObjectsRetained)
+	}
+	return removed, errors.Join(errs...)
+}
+
+// removeVersions removes the listed object versions, it reports how many were removed and
+// whether some are retained apart from the other errors.
+func (c *client) removeVersions(ctx context.Context, name string, batch []minio.ObjectInfo) (int, bool, []error) {
+	objects := make(chan minio.ObjectInfo, len(batch))
+	for _, object := range batch {
+		objects <- object
+	}
+	close(objects)
+
+	removed, retained, errs := 0, false, []error{}
+	removeOpts := minio.RemoveObjectsOptions{GovernanceBypass: true}
+	for result := range c.RemoveObjectsWithResult(ctx, name, objects, removeOpts) {
+		switch {
+		case isRetained(result.Err):
+			retained = true
+		case result.Err != nil:
+			errs = append(errs, result.Err)
+		default:
+			removed++
+		}
+	}
+	return removed, retained, errs
+}
+
+// PolicyChanges tells what PolicyReconcile changed in MinIO.
+type PolicyChanges struct {
+	// PolicyCreated is set when the policy and its user, if any, were created, or the
+	// attachment of the user was restored
+	PolicyCreated bool
+	// PolicyUpdated is set when the statements of an existing policy were replaced
+	PolicyUpdated bool
+	// UserCreated is set when the user was created and attached to an existing policy
+	UserCreated bool
+	// UserRecreated is set when the user attached to the policy was replaced by a new one
+	UserRecreated bool
+	// UsersDetached is set when a policy bound to external identities was detached from MinIO users
+	UsersDetached bool
+	// IdentitiesUpdated is set when the policy was attached to or detached from LDAP users or groups
+	IdentitiesUpdated bool
 }
 
 func (c *client) policyCreate(ctx context.Context, policy *Policy) error {
-	p, err := json.Marshal(policy.Policy)
+	p, err := policyDocument(policy)
 	if err != nil {
 		return err
 	}
-
 	if err := c.AddCannedPolicy(ctx, policy.Name, p); err != nil {
 		return err
 	}
@@ -105,59 +279,196 @@ func (c *client) policyCreate(ctx context.Context, policy *Policy) error {
 }
 
 func (c *client) userCreate(ctx context.Context, policy *Policy) error {
-	if err := c.AddUser(ctx, policy.User.Name, policy.User.Password); err != nil {
+	password := policy.User.Password
+	if password == "" {
+		generated, err := GenerateSecretKey(0, nil)
+		if err != nil {
+			return err
+		}
+		password = string(generated)
+	}
+	if err := c.AddUser(ctx, policy.User.Name, password); err != nil {
 		return err
 	}
 	association := madmin.PolicyAssociationReq{
 		Policies: []string{policy.Name},
 		User:     policy.User.Name,
 	}
-	if _, err := c.AttachPolicy(ctx, association); err != nil {
+	// the attachment may have survived the removal of the policy or the user
+	if _, err := c.AttachPolicy(ctx, association); err != nil &&
+		madmin.ToErrorResponse(err).Code != errPolicyAlreadyApplied {
 		return err
 	}
 	return nil
 }
 
-func (c *client) PolicyReconcile(ctx context.Context, policy *Policy) error {
+// policyDocument returns the JSON sent to MinIO for the policy, marked with ManagedPolicyID.
+func policyDocument(policy *Policy) ([]byte, error) {
+	if policy.Document == nil {
+		document := *policy.Policy
+		document.ID = ManagedPolicyID
+		return json.Marshal(document)
+	}
+	// the raw document is forwarded as is, only its ID is set
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(policy.Document, &fields); err != nil {
+		return nil, err
+	}
+	fields["ID"], _ = json.Marshal(ManagedPolicyID)
+	return json.Marshal(fields)
+}
+
+// cannedPolicy returns the document of the canned policy, nil when it does not exist.
+func (c *client) cannedPolicy(ctx context.Context, name string) ([]byte, error) {
+	current, err := c.InfoCannedPolicyV2(ctx, name)
+	if madmin.ToErrorResponse(err).Code == errNoSuchPolicy {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return current.Policy, nil
+}
+
+// isManagedPolicy tells if the canned policy document carries ManagedPolicyID.
+func isManagedPolicy(document []byte) bool {
+	parsed := struct{ ID string }{}
+	return json.Unmarshal(document, &parsed) == nil && parsed.ID == ManagedPolicyID
+}
+
+// decideManaged tells if the canned policy may be managed by the controller: it does not exist,
+// it carries ManagedPolicyID, or it was created before the ID was recorded and is attached to user.
+func decideManaged(current []byte, attached []string, user string) bool {
+	return len(current) == 0 || isManagedPolicy(current) || (user != "" && slices.Contains(attached, user))
+}
+
+// mappedUsers returns the MinIO users of the policy mappings.
+func mappedUsers(result madmin.PolicyEntitiesResult) []string {
+	users := []string{}
+	for _, mapping := range result.PolicyMappings {
+		users = append(users, mapping.Users...)
+	}
+	return users
+}
+
+// PolicyReconcile converges the canned policy and its user, it returns ErrPolicyNotManaged
+// instead of taking over a canned policy which was not created by the controller.
+func (c *client) PolicyReconcile(ctx context.Context, policy *Policy) (PolicyChanges, error) {
+	changes := PolicyChanges{}
 	entities := madmin.PolicyEntitiesQuery{Policy: []string{policy.Name}}
 	results, err := c.GetPolicyEntities(ctx, entities)
 	if err != nil {
-		return err
+		return changes, err
+	}
+	// a policy removed while still attached is recreated from an empty document
+	current, err := c.cannedPolicy(ctx, policy.Name)
+	if err != nil {
+		return changes, err
+	}
+	if !decideManaged(current, mappedUsers(results), policy.User.Name) {
+		return changes, fmt.Errorf("%w: %s", ErrPolicyNotManaged, policy.Name)
 	}
 	if len(results.PolicyMappings) == 0 {
-		return c.policyCreate(ctx, policy)
+		changes.PolicyCreated = true
+		return changes, c.policyCreate(ctx, policy)
 	}
+
+	expected, err := decideCannedPolicy(current, policy)
+	if err != nil {
+		return changes, err
+	}
+	if expected != nil {
+		if err := c.AddCannedPolicy(ctx, policy.Name, expected); err != nil {
+			return changes, err
+		}
+		changes.PolicyUpdated = true
+	}
+
 	if len(results.PolicyMappings[0].Users) == 0 {
-		return c.userCreate(ctx, policy)
+		changes.UserCreated = true
+		return changes, c.userCreate(ctx, policy)
 	}
 	user := results.PolicyMappings[0].Users[0]
 	if policy.User.Name == user {
+		if policy.User.Password == "" {
+			return changes, nil
+		}
 		// update current user with password
-		return c.SetUser(ctx, policy.User.Name, policy.User.Password, madmin.AccountEnabled)
+		return changes, c.SetUser(ctx, policy.User.Name, policy.User.Password, madmin.AccountEnabled)
 	}
 	// delete old user and set new one
 	if err := c.RemoveUser(ctx, user); err != nil {
-		return err
+		return changes, err
 	}
-	return c.userCreate(ctx, policy)
+	changes.UserRecreated = true
+	return changes, c.userCreate(ctx, policy)
 }
 
-func (c *client) PolicyDelete(ctx context.Context, policy string) error {
-	query := madmin.PolicyEntitiesQuery{Policy: []string{policy}}
-	result, err := c.GetPolicyEntities(ctx, query)
+// decideCannedPolicy returns the document to store in MinIO, nil when the current one is equivalent.
+func decideCannedPolicy(current []byte, wanted *Policy) ([]byte, error) {
+	expected, err := policyDocument(wanted)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return expected, nil
+	}
+	currentPolicy, err := policy.ParseConfig(bytes.NewReader(current))
+	if err != nil {
+		// unreadable policies are overwritten
+		return expected, nil
+	}
+	expectedPolicy, err := policy.ParseConfig(bytes.NewReader(expected))
+	if err != nil {
+		return nil, err
+	}
+	if currentPolicy.Equals(*expectedPolicy) {
+		return nil, nil
+	}
+	return expected, nil
+}
+
+// PolicyDelete removes the canned policy and the users attached to it, after detaching it from its
+// groups, a canned policy which was not created by the controller is left untouched unless it
+// predates ManagedPolicyID and is attached to user, the user of the resource. The users of
+// every mapping are removed, and the canned policy is removed even without mapping: GetPolicyEntities
+// returns none when the controller crashed between AddCannedPolicy and AttachPolicy. A canned policy
+// already removed is not an error, so that the deletion can be retried.
+func (c *client) PolicyDelete(ctx context.Context, policy, user string) error {
+	current, err := c.cannedPolicy(ctx, policy)
 	if err != nil {
 		return err
 	}
-	if result.PolicyMappings == nil {
-		return nil
+	query := madmin.PolicyEntitiesQuery{Policy: []string{policy}}
+	result, err := c.GetPolicyEntities(ctx, query)
+	if err != nil || !decideManaged(current, mappedUsers(result), user) {
+		return err
 	}
 	errs := []error{}
-	for _, user := range result.PolicyMappings[0].Users {
+	for _, user := range mappedUsers(result) {
 		if err := c.RemoveUser(ctx, user); err != nil {
 			errs = append(errs, err)
 		}
 	}
-	return errors.Join(append(errs, c.RemoveCannedPolicy(ctx, policy))...)
+	// MinIO refuses to remove a canned policy still attached to a group
+	for _, mapping := range result.PolicyMappings {
+		for _, group := range mapping.Groups {
+			_, err := c.DetachPolicy(ctx, madmin.PolicyAssociationReq{Policies: []string{policy}, Group: group})
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	// the LDAP identities bound to the policy are released with it
+	if users, groups, err := c.ldapEntities(ctx, policy); err == nil {
+		errs = append(errs, c.ldapDetach(ctx, policy, users, groups))
+	} else if madmin.ToErrorResponse(err).Code != errLDAPNotEnabled {
+		errs = append(errs, err)
+	}
+	if err := c.RemoveCannedPolicy(ctx, policy); madmin.ToErrorResponse(err).Code != errNoSuchPolicy {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
 }
 
 func (c *client) BucketPolicyReconcile(ctx context.Context, name string, policy BucketPolicy) (bool, error) {
@@ -211,15 +522,107 @@ func decidePolicy(bucket, currentJSON string, wantedPolicy BucketPolicy) ([]byte
 	return wantedJSON, nil
 }
 
+// BucketVersioningReconcile converges the versioning configuration of the bucket
+// and returns the versioning status in effect. A nil versioning leaves the bucket untouched.
+func (c *client) BucketVersioningReconcile(
+	ctx context.Context, name string, versioning *BucketVersioning,
+) (VersioningStatus, bool, error) {
+	current, err := c.GetBucketVersioning(ctx, name)
+	if err != nil {
+		return "", false, err
+	}
+	expected := decideVersioning(current, versioning)
+	if expected == nil {
+		return VersioningStatus(current.Status), false, nil
+	}
+	if err := c.SetBucketVersioning(ctx, name, *expected); err != nil {
+		return VersioningStatus(current.Status), false, err
+	}
+	return VersioningStatus(expected.Status), true, nil
+}
+
+func decideVersioning(
+	current minio.BucketVersioningConfiguration, wanted *BucketVersioning,
+) *minio.BucketVersioningConfiguration {
+
+	if wanted == nil {
+		return nil
+	}
+	expected := minio.BucketVersioningConfiguration{Status: string(wanted.Status)}
+	// excluded prefixes and folders are a MinIO extension only valid on enabled versioning
+	if wanted.Status == v1alpha1.VersioningEnabled {
+		expected.ExcludeFolders = wanted.ExcludeFolders
+		for _, prefix := range wanted.ExcludedPrefixes {
+			expected.ExcludedPrefixes = append(expected.ExcludedPrefixes, minio.ExcludedPrefix{Prefix: prefix})
+		}
+	}
+	if current.Status != expected.Status || current.ExcludeFolders != expected.ExcludeFolders ||
+		len(current.ExcludedPrefixes) != len(expected.ExcludedPrefixes) {
+		return &expected
+	}
+	prefixes := make(map[string]struct{}, len(current.ExcludedPrefixes))
+	for _, prefix := range current.ExcludedPrefixes {
+		prefixes[prefix.Prefix] = empty
+	}
+	for _, prefix := range expected.ExcludedPrefixes {
+		if _, ok := prefixes[prefix.Prefix]; !ok {
+			return &expected
+		}
+	}
+	return nil
+}
+
 type stub struct{}
 
-func (s stub) BucketCreate(context.Context, string) error         { return nil }
-func (s stub) BucketExists(context.Context, string) (bool, error) { return true, nil }
-func (s stub) BucketDelete(context.Context, string) error         { return nil }
-func (s stub) PolicyReconcile(context.Context, *Policy) error     { return nil }
-func (s stub) PolicyDelete(context.Context, string) error         { return nil }
+func (s stub) BucketCreate(context.Context, string, bool) error      { return nil }
+func (s stub) BucketExists(context.Context, string) (bool, error)    { return true, nil }
+func (s stub) BucketDelete(context.Context, string) error            { return nil }
+func (s stub) BucketPurge(context.Context, string, int) (int, error) { return 0, nil }
+func (s stub) BucketClaim(context.Context, string, string, bool) (bool, error) {
+	return false, nil
+}
+func (s stub) BucketCheckClaim(context.Context, string, string, bool) error { return nil }
+func (s stub) BucketRelease(context.Context, string, string) error          { return nil }
+func (s stub) PolicyReconcile(context.Context, *Policy) (PolicyChanges, error) {
+	return PolicyChanges{}, nil
+}
+func (s stub) PolicyDelete(context.Context, string, string) error { return nil }
+func (s stub) IdentityPolicyReconcile(context.Context, *Policy, Identities) (PolicyChanges, error) {
+	return PolicyChanges{}, nil
+}
 func (s stub) BucketPolicyReconcile(context.Context, string, BucketPolicy) (bool, error) {
 	return false, nil
 }
+func (s stub) BucketVersioningReconcile(context.Context, string, *BucketVersioning) (VersioningStatus, bool, error) {
+	return "", false, nil
+}
+func (s stub) BucketLifecycleReconcile(context.Context, string, *BucketLifecycle) (bool, error) {
+	return false, nil
+}
+func (s stub) BucketQuotaReconcile(context.Context, string, uint64) (bool, error) { return false, nil }
+func (s stub) BucketUsage(context.Context, string) (uint64, error)                { return 0, nil }
+func (s stub) BucketsUsage(context.Context) (map[string]UsageInfo, error)         { return nil, nil }
+func (s stub) BucketObjectLockReconcile(context.Context, string, *BucketObjectLock) (bool, error) {
+	return false, nil
+}
+func (s stub) BucketEncryptionReconcile(context.Context, string, *BucketEncryption) (bool, error) {
+	return false, nil
+}
+func (s stub) ServiceAccountReconcile(context.Context, ServiceAccount) (bool, error) {
+	return false, nil
+}
+func (s stub) ServiceAccountDelete(context.Context, string, string) error { return nil }
+func (s stub) UserDelete(context.Context, string) error                   { return nil }
+func (s stub) CannedPolicyDelete(context.Context, string) error           { return nil }
+func (s stub) Inventory(context.Context) (Inventory, error)               { return Inventory{}, nil }
+func (s stub) Health(context.Context) error                               { return nil }
+func (s stub) Endpoint() Endpoint                                         { return Endpoint{Region: defaultLocation} }
+func (s stub) AssumeRole(context.Context, string, time.Duration) (TemporaryCredentials, error) {
+	return TemporaryCredentials{}, nil
+}
+func (s stub) GroupReconcile(context.Context, Group) (GroupChanges, error) {
+	return GroupChanges{}, nil
+}
+func (s stub) GroupDelete(context.Context, string) error { return nil }
 
 func NewStub() Client { return stub{} }
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/v3/policy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -53,3 +55,143 @@ func Test_decidePolicy(t *testing.T) {
 
 	}
 }
+
+var versioningEnabled = &BucketVersioning{Status: v1alpha1.VersioningEnabled}
+
+var decideVersioningEntries = []struct {
+	current  minio.BucketVersioningConfiguration
+	wanted   *BucketVersioning
+	expected *minio.BucketVersioningConfiguration
+}{
+	{minio.BucketVersioningConfiguration{}, nil, nil},
+	{minio.BucketVersioningConfiguration{Status: minio.Enabled}, nil, nil},
+	{
+		minio.BucketVersioningConfiguration{}, versioningEnabled,
+		&minio.BucketVersioningConfiguration{Status: minio.Enabled},
+	},
+	{minio.BucketVersioningConfiguration{Status: minio.Enabled}, versioningEnabled, nil},
+	{
+		minio.BucketVersioningConfiguration{Status: minio.Enabled},
+		&BucketVersioning{Status: v1alpha1.VersioningSuspended, ExcludeFolders: true},
+		&minio.BucketVersioningConfiguration{Status: minio.Suspended},
+	},
+	{
+		minio.BucketVersioningConfiguration{
+			Status:           minio.Enabled,
+			ExcludedPrefixes: []minio.ExcludedPrefix{{Prefix: "b/"}, {Prefix: "a/"}},
+		},
+		&BucketVersioning{Status: v1alpha1.VersioningEnabled, ExcludedPrefixes: []string{"a/", "b/"}},
+		nil,
+	},
+	{
+		minio.BucketVersioningConfiguration{
+			Status:           minio.Enabled,
+			ExcludedPrefixes: []minio.ExcludedPrefix{{Prefix: "a/"}},
+		},
+		&BucketVersioning{Status: v1alpha1.VersioningEnabled, ExcludedPrefixes: []string{"b/"}},
+		&minio.BucketVersioningConfiguration{
+			Status:           minio.Enabled,
+			ExcludedPrefixes: []minio.ExcludedPrefix{{Prefix: "b/"}},
+		},
+	},
+}
+
+func Test_decideVersioning(t *testing.T) {
+	for _, entry := range decideVersioningEntries {
+		assert.Equal(t, entry.expected, decideVersioning(entry.current, entry.wanted))
+	}
+}
+
+var isRetainedEntries = []struct {
+	err      error
+	expected bool
+}{
+	{nil, false},
+	{minio.ErrorResponse{Code: errObjectLocked, Message: "Object is WORM protected and cannot be overwritten"}, true},
+	{minio.ErrorResponse{Code: errObjectLocked, Message: "Invalid version id specified"}, false},
+	{minio.ErrorResponse{Code: "AccessDenied", Message: "Access Denied."}, false},
+}
+
+func Test_isRetained(t *testing.T) {
+	for _, entry := range isRetainedEntries {
+		assert.Equal(t, entry.expected, isRetained(entry.err), "%v", entry.err)
+	}
+}
+
+func Test_decideCannedPolicy(t *testing.T) {
+	wanted := NewDefaultPolicy(bucketName)
+	current, err := policyDocument(wanted)
+	require.NoError(t, err)
+
+	expected, err := decideCannedPolicy(current, wanted)
+	require.NoError(t, err)
+	assert.Nil(t, expected, "equivalent policies are left untouched")
+
+	expected, err = decideCannedPolicy(nil, wanted)
+	require.NoError(t, err)
+	assertSamePolicy(t, current, expected)
+
+	changed, err := policyDocument(NewDefaultPolicy("other"))
+	require.NoError(t, err)
+	expected, err = decideCannedPolicy(changed, wanted)
+	require.NoError(t, err)
+	assertSamePolicy(t, current, expected)
+
+	unmarked, err := json.Marshal(wanted.Policy)
+	require.NoError(t, err)
+	expected, err = decideCannedPolicy(unmarked, wanted)
+	require.NoError(t, err)
+	assertSamePolicy(t, current, expected)
+
+	document := &Policy{Document: []byte(`{"Version":"2012-10-17","Statement":[],"Custom":true}`)}
+	expected, err = decideCannedPolicy(current, document)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ID":"`+ManagedPolicyID+`","Version":"2012-10-17","Statement":[],"Custom":true}`,
+		string(expected), "raw documents are sent as is, only marked")
+}
+
+var decideManagedEntries = []struct {
+	current  []byte
+	attached []string
+	expected bool
+}{
+	{nil, nil, true},
+	{[]byte(`{"ID":"` + ManagedPolicyID + `","Version":"2012-10-17","Statement":[]}`), []string{"admin"}, true},
+	{[]byte(`{"Version":"2012-10-17","Statement":[]}`), []string{"user"}, true},
+	{[]byte(`{"Version":"2012-10-17","Statement":[]}`), []string{"admin"}, false},
+	{[]byte(`{"Version":"2012-10-17","Statement":[]}`), nil, false},
+	{[]byte(`{"ID":"other","Version":"2012-10-17","Statement":[]}`), nil, false},
+}
+
+func Test_decideManaged(t *testing.T) {
+	for _, entry := range decideManagedEntries {
+		assert.Equal(t, entry.expected, decideManaged(entry.current, entry.attached, "user"), "%s", entry.current)
+	}
+}
+
+var mappedUsersEntries = []struct {
+	result   madmin.PolicyEntitiesResult
+	expected []string
+}{
+	// a canned policy left without user by a crash before AttachPolicy
+	{madmin.PolicyEntitiesResult{}, []string{}},
+	{madmin.PolicyEntitiesResult{PolicyMappings: []madmin.PolicyEntities{
+		{Policy: "bucket", Users: []string{"user"}},
+		{Policy: "bucket", Users: []string{"previous"}, Groups: []string{"team"}},
+	}}, []string{"user", "previous"}},
+}
+
+func Test_mappedUsers(t *testing.T) {
+	for _, entry := range mappedUsersEntries {
+		assert.Equal(t, entry.expected, mappedUsers(entry.result))
+	}
+}
+
+// assertSamePolicy compares the documents semantically, actions are sets marshaled in any order.
+func assertSamePolicy(t *testing.T, expected, actual []byte) {
+	expectedPolicy, err := policy.ParseConfig(bytes.NewReader(expected))
+	require.NoError(t, err)
+	actualPolicy, err := policy.ParseConfig(bytes.NewReader(actual))
+	require.NoError(t, err)
+	assert.True(t, expectedPolicy.Equals(*actualPolicy), "%s", actual)
+}
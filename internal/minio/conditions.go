@@ -0,0 +1,88 @@
+package minio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/pkg/v3/policy/condition"
+)
+
+var ErrInvalidCondition = errors.New("invalid condition")
+
+// conditionSet mirrors the Condition block of a statement: operator, key and values.
+type conditionSet map[string]map[string][]string
+
+func (s conditionSet) add(operator, key string, values ...string) {
+	if s[operator] == nil {
+		s[operator] = map[string][]string{}
+	}
+	s[operator][key] = append(s[operator][key], values...)
+}
+
+// parse relies on the policy package to validate operators, keys and values.
+func (s conditionSet) parse() (condition.Functions, error) {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	functions := condition.Functions{}
+	return functions, functions.UnmarshalJSON(raw)
+}
+
+// NewConditions translates the typed conditions of a statement into policy conditions,
+// failures are reported as a *StatementError wrapping ErrInvalidCondition.
+func NewConditions(conditions *v1alpha1.StatementConditions) (condition.Functions, error) {
+	if conditions == nil {
+		return nil, nil
+	}
+	set, invalid := conditionSet{}, error(nil)
+	// add validates each condition on its own to locate the first invalid field
+	add := func(field string, index int, operator, key string, values ...string) {
+		if invalid != nil {
+			return
+		}
+		if _, err := (conditionSet{operator: {key: values}}).parse(); err != nil {
+			value := fmt.Sprint(values)
+			if len(values) == 1 {
+				value = values[0]
+			}
+			invalid = &StatementError{Field: "conditions." + field, Index: index, Value: value,
+				Err: fmt.Errorf("%w: %s", ErrInvalidCondition, err)}
+			return
+		}
+		set.add(operator, key, values...)
+	}
+
+	for i, ip := range conditions.SourceIPs {
+		add("sourceIPs", i, "IpAddress", "aws:SourceIp", ip)
+	}
+	for i, ip := range conditions.NotSourceIPs {
+		add("notSourceIPs", i, "NotIpAddress", "aws:SourceIp", ip)
+	}
+	if secure := conditions.SecureTransport; secure != nil {
+		add("secureTransport", -1, "Bool", "aws:SecureTransport", strconv.FormatBool(*secure))
+	}
+	if date := conditions.NotBefore; date != nil {
+		add("notBefore", -1, "DateGreaterThanEquals", "aws:CurrentTime", date.UTC().Format(time.RFC3339))
+	}
+	if date := conditions.NotAfter; date != nil {
+		add("notAfter", -1, "DateLessThan", "aws:CurrentTime", date.UTC().Format(time.RFC3339))
+	}
+	if len(conditions.Prefixes) > 0 {
+		add("prefixes", -1, "StringLike", "s3:prefix", conditions.Prefixes...)
+	}
+	for i, custom := range conditions.Custom {
+		add("custom", i, custom.Operator, custom.Key, custom.Values...)
+	}
+	if invalid != nil {
+		return nil, invalid
+	}
+	if len(set) == 0 {
+		return nil, nil
+	}
+	return set.parse()
+}
@@ -0,0 +1,109 @@
+package minio
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/pkg/v3/policy/condition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewConditions(t *testing.T) {
+	functions, err := NewConditions(nil)
+	require.NoError(t, err)
+	assert.Nil(t, functions)
+
+	start := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	functions, err = NewConditions(&v1alpha1.StatementConditions{
+		SourceIPs:       []string{"10.0.0.0/8", "192.168.1.1"},
+		SecureTransport: ptr(true),
+		NotBefore:       &start,
+		Prefixes:        []string{"logs/*"},
+		Custom:          []v1alpha1.Condition{{Operator: "StringEquals", Key: "s3:delimiter", Values: []string{"/"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string][]string{
+		"IpAddress":             {"aws:SourceIp": {"10.0.0.0/8", "192.168.1.1/32"}},
+		"Bool":                  {"aws:SecureTransport": {"true"}},
+		"DateGreaterThanEquals": {"aws:CurrentTime": {"2025-01-01T00:00:00Z"}},
+		"StringLike":            {"s3:prefix": {"logs/*"}},
+		"StringEquals":          {"s3:delimiter": {"/"}},
+	}, sortedConditions(t, functions))
+}
+
+// sortedConditions returns the values of the functions by operator and key, sorted as they
+// are sets marshaled in any order.
+func sortedConditions(t *testing.T, functions condition.Functions) map[string]map[string][]string {
+	raw, err := json.Marshal(functions)
+	require.NoError(t, err)
+	conditions := map[string]map[string][]string{}
+	require.NoError(t, json.Unmarshal(raw, &conditions))
+	for _, keys := range conditions {
+		for _, values := range keys {
+			slices.Sort(values)
+		}
+	}
+	return conditions
+}
+
+var invalidConditionsEntries = []struct {
+	name       string
+	conditions v1alpha1.StatementConditions
+	expected   *StatementError
+}{
+	{
+		name:       "invalid CIDR",
+		conditions: v1alpha1.StatementConditions{NotSourceIPs: []string{"10.0.0.0/8", "10.0.0.0/33"}},
+		expected:   &StatementError{Field: "conditions.notSourceIPs", Index: 1, Value: "10.0.0.0/33"},
+	},
+	{
+		name: "unknown operator",
+		conditions: v1alpha1.StatementConditions{Custom: []v1alpha1.Condition{
+			{Operator: "StringMaybe", Key: "s3:delimiter", Values: []string{"/"}},
+		}},
+		expected: &StatementError{Field: "conditions.custom", Index: 0, Value: "/"},
+	},
+	{
+		name: "unknown key",
+		conditions: v1alpha1.StatementConditions{Custom: []v1alpha1.Condition{
+			{Operator: "StringEquals", Key: "s3:delimiter", Values: []string{"/"}},
+			{Operator: "StringEquals", Key: "s3:nope", Values: []string{"a", "b"}},
+		}},
+		expected: &StatementError{Field: "conditions.custom", Index: 1, Value: "[a b]"},
+	},
+}
+
+func TestNewConditions_invalid(t *testing.T) {
+	for _, entry := range invalidConditionsEntries {
+		t.Run(entry.name, func(t *testing.T) {
+			_, err := NewConditions(&entry.conditions)
+			assert.ErrorIs(t, err, ErrInvalidCondition)
+			var actual *StatementError
+			require.ErrorAs(t, err, &actual)
+			assert.Equal(t, entry.expected.Field, actual.Field)
+			assert.Equal(t, entry.expected.Index, actual.Index)
+			assert.Equal(t, entry.expected.Value, actual.Value)
+		})
+	}
+}
+
+func TestNewStatement_conditions(t *testing.T) {
+	statement := v1alpha1.Statement{
+		Effect:     "Allow",
+		Actions:    []string{"s3:ListBucket"},
+		Conditions: &v1alpha1.StatementConditions{Prefixes: []string{"logs/*"}},
+	}
+	actual, err := NewStatement("foo", statement)
+	require.NoError(t, err)
+	assert.Len(t, actual.Conditions, 1)
+
+	// MinIO only supports s3:prefix on listing actions
+	statement.Actions = []string{"s3:GetObject"}
+	_, err = NewStatement("foo", statement)
+	assert.ErrorIs(t, err, ErrInvalidCondition)
+}
@@ -62,6 +62,20 @@ func GenerateAccessKey(length int, random io.Reader) ([]byte, error) {
 	return key, nil
 }
 
+// GeneratedUserPrefix starts the name of the users generated by the controller, it is
+// how the orphan collection recognizes them.
+const GeneratedUserPrefix = "mctl"
+
+// GenerateUser returns a new user name made of GeneratedUserPrefix followed by random
+// alphanumeric characters, as long as a generated access key.
+func GenerateUser() ([]byte, error) {
+	key, err := GenerateAccessKey(accessKeyMaxLen-len(GeneratedUserPrefix), nil)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(GeneratedUserPrefix), key...), nil
+}
+
 // GenerateSecretKey returns a new secret key generated randomly using
 // the given io.Reader. If random is nil, crypto/rand.Reader is used.
 // If length <= 0, the secret key length is chosen automatically.
@@ -0,0 +1,56 @@
+package minio
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/sse"
+)
+
+const (
+	errNoSuchEncryption = "ServerSideEncryptionConfigurationNotFoundError"
+	// MinIO answers NotImplemented when server-side encryption is requested without a KMS
+	errKMSNotConfigured = "NotImplemented"
+)
+
+var ErrKMSNotConfigured = errors.New("server-side encryption requires a KMS configured on the MinIO server")
+
+type BucketEncryption = v1alpha1.BucketEncryption
+
+// BucketEncryptionReconcile converges the default server-side encryption of the bucket.
+// A nil encryption leaves the bucket untouched, ErrKMSNotConfigured is returned when
+// the MinIO server is unable to encrypt objects.
+func (c *client) BucketEncryptionReconcile(ctx context.Context, name string, wanted *BucketEncryption) (bool, error) {
+	if wanted == nil {
+		return false, nil
+	}
+	current, err := c.GetBucketEncryption(ctx, name)
+	if minio.ToErrorResponse(err).Code == errNoSuchEncryption {
+		current, err = &sse.Configuration{}, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	expected := decideEncryption(current, wanted)
+	if expected == nil {
+		return false, nil
+	}
+	err = c.SetBucketEncryption(ctx, name, expected)
+	if minio.ToErrorResponse(err).Code == errKMSNotConfigured {
+		return false, ErrKMSNotConfigured
+	}
+	return err == nil, err
+}
+
+func decideEncryption(current *sse.Configuration, wanted *BucketEncryption) *sse.Configuration {
+	expected := sse.NewConfigurationSSES3()
+	if wanted.Algorithm == v1alpha1.EncryptionSSEKMS {
+		expected = sse.NewConfigurationSSEKMS(wanted.KMSKeyID)
+	}
+	if len(current.Rules) == 1 && current.Rules[0].Apply == expected.Rules[0].Apply {
+		return nil
+	}
+	return expected
+}
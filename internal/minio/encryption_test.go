@@ -0,0 +1,39 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"github.com/stretchr/testify/assert"
+)
+
+var decideEncryptionEntries = []struct {
+	current  *sse.Configuration
+	wanted   *BucketEncryption
+	expected *sse.Configuration
+}{
+	{&sse.Configuration{}, &BucketEncryption{Algorithm: v1alpha1.EncryptionSSES3}, sse.NewConfigurationSSES3()},
+	{sse.NewConfigurationSSES3(), &BucketEncryption{Algorithm: v1alpha1.EncryptionSSES3}, nil},
+	{
+		sse.NewConfigurationSSES3(),
+		&BucketEncryption{Algorithm: v1alpha1.EncryptionSSEKMS, KMSKeyID: "audit"},
+		sse.NewConfigurationSSEKMS("audit"),
+	},
+	{
+		sse.NewConfigurationSSEKMS("audit"),
+		&BucketEncryption{Algorithm: v1alpha1.EncryptionSSEKMS, KMSKeyID: "audit"},
+		nil,
+	},
+	{
+		sse.NewConfigurationSSEKMS("audit"),
+		&BucketEncryption{Algorithm: v1alpha1.EncryptionSSEKMS, KMSKeyID: "logs"},
+		sse.NewConfigurationSSEKMS("logs"),
+	},
+}
+
+func Test_decideEncryption(t *testing.T) {
+	for _, entry := range decideEncryptionEntries {
+		assert.Equal(t, entry.expected, decideEncryption(entry.current, entry.wanted))
+	}
+}
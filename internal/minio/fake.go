@@ -0,0 +1,871 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/madmin-go/v3"
+)
+
+var (
+	// ErrFakeNoSuchBucket is returned by the fake for the operations on a missing bucket.
+	ErrFakeNoSuchBucket = errors.New("the specified bucket does not exist")
+	// ErrFakeAccessDenied is returned by AssumeRole for unknown users.
+	ErrFakeAccessDenied = errors.New("access denied")
+)
+
+// FakeBucket is the state of a bucket in the fake.
+type FakeBucket struct {
+	// Owner is the claim recorded on the bucket
+	Owner         string
+	ObjectLocking bool
+	// Policy is the anonymous policy, a new bucket is private
+	Policy     BucketPolicy
+	Versioning *BucketVersioning
+	Lifecycle  *BucketLifecycle
+	Quota      uint64
+	Lock       *BucketObjectLock
+	Encryption *BucketEncryption
+	// Objects is the number of object versions removed by BucketPurge
+	Objects int
+	// Retained is the number of object versions under compliance retention, never purged
+	Retained int
+	Usage    UsageInfo
+}
+
+// FakeUser is the state of a user in the fake.
+type FakeUser struct {
+	Password string
+	Policies []string
+}
+
+// FakeSession is a set of temporary credentials issued by the fake.
+type FakeSession struct {
+	Parent string
+	TemporaryCredentials
+}
+
+// FakeGroup is the state of a group in the fake.
+type FakeGroup struct {
+	Members, Policies []string
+	Disabled          bool
+}
+
+// Fake is an in-memory MinIO implementing Client for the tests. Failures and latency
+// are injected per method with SetError and SetLatency, the state is inspected with
+// the Bucket, CannedPolicy, User, ServiceAccount, Session, Group and LDAPBindings helpers.
+// The LDAP users and groups are distinguished names mapped to their policies.
+type Fake struct {
+	mutex           sync.Mutex
+	buckets         map[string]*FakeBucket
+	policies        map[string][]byte
+	users           map[string]*FakeUser
+	serviceAccounts map[string]ServiceAccount
+	sessions        map[string]FakeSession
+	groups          map[string]*FakeGroup
+	ldapUsers       map[string][]string
+	ldapGroups      map[string][]string
+	errors          map[string]error
+	latency         map[string]time.Duration
+	calls           map[string]int
+}
+
+var _ Client = &Fake{}
+
+// NewFake returns an empty fake.
+func NewFake() *Fake {
+	return &Fake{
+		buckets:         map[string]*FakeBucket{},
+		policies:        map[string][]byte{},
+		users:           map[string]*FakeUser{},
+		serviceAccounts: map[string]ServiceAccount{},
+		sessions:        map[string]FakeSession{},
+		groups:          map[string]*FakeGroup{},
+		ldapUsers:       map[string][]string{},
+		ldapGroups:      map[string][]string{},
+		errors:          map[string]error{},
+		latency:         map[string]time.Duration{},
+		calls:           map[string]int{},
+	}
+}
+
+// SetError makes the method fail with err until it is reset with a nil error.
+func (f *Fake) SetError(method string, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err == nil {
+		delete(f.errors, method)
+		return
+	}
+	f.errors[method] = err
+}
+
+// SetLatency delays the calls of the method, a zero latency removes the delay.
+func (f *Fake) SetLatency(method string, latency time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.latency[method] = latency
+}
+
+// Calls returns the number of calls of the method, failed ones included.
+func (f *Fake) Calls(method string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.calls[method]
+}
+
+// Bucket returns a copy of the state of the bucket.
+func (f *Fake) Bucket(name string) (FakeBucket, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, ok := f.buckets[name]
+	if !ok {
+		return FakeBucket{}, false
+	}
+	return *bucket, true
+}
+
+// Buckets returns the sorted names of the buckets.
+func (f *Fake) Buckets() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return slices.Sorted(maps.Keys(f.buckets))
+}
+
+// SetBucket creates or replaces the bucket, to simulate changes made behind the controller.
+func (f *Fake) SetBucket(name string, bucket FakeBucket) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.buckets[name] = &bucket
+}
+
+// CannedPolicy returns the document of the canned policy.
+func (f *Fake) CannedPolicy(name string) ([]byte, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	document, ok := f.policies[name]
+	return document, ok
+}
+
+// SetCannedPolicy creates or replaces the canned policy, as done by an administrator.
+func (f *Fake) SetCannedPolicy(name string, document []byte) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.policies[name] = slices.Clone(document)
+}
+
+// RemoveCannedPolicy removes the canned policy and leaves its attachments, as done by mc.
+func (f *Fake) RemoveCannedPolicy(name string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.policies, name)
+}
+
+// User returns a copy of the user.
+func (f *Fake) User(name string) (FakeUser, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	user, ok := f.users[name]
+	if !ok {
+		return FakeUser{}, false
+	}
+	return FakeUser{Password: user.Password, Policies: slices.Clone(user.Policies)}, true
+}
+
+// Users returns the sorted names of the users.
+func (f *Fake) Users() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return slices.Sorted(maps.Keys(f.users))
+}
+
+// ServiceAccount returns the service account with the given access key.
+func (f *Fake) ServiceAccount(accessKey string) (ServiceAccount, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	account, ok := f.serviceAccounts[accessKey]
+	return account, ok
+}
+
+// Session returns the temporary credentials with the given access key.
+func (f *Fake) Session(accessKey string) (FakeSession, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	session, ok := f.sessions[accessKey]
+	return session, ok
+}
+
+// Group returns a copy of the group.
+func (f *Fake) Group(name string) (FakeGroup, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	group, ok := f.groups[name]
+	if !ok {
+		return FakeGroup{}, false
+	}
+	return FakeGroup{
+		Members: slices.Clone(group.Members), Policies: slices.Clone(group.Policies), Disabled: group.Disabled,
+	}, true
+}
+
+// SetGroup creates or replaces the group, to simulate changes made behind the controller.
+func (f *Fake) SetGroup(name string, group FakeGroup) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.groups[name] = &group
+}
+
+// LDAPBindings returns the sorted LDAP users and groups the policy is attached to.
+func (f *Fake) LDAPBindings(policy string) Identities {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return Identities{LDAPUsers: bound(f.ldapUsers, policy), LDAPGroups: bound(f.ldapGroups, policy)}
+}
+
+// bound returns the sorted entities mapped to the policy.
+func bound(entities map[string][]string, policy string) []string {
+	names := []string{}
+	for name, policies := range entities {
+		if slices.Contains(policies, policy) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// enter records the call, waits for the injected latency and returns the injected error.
+func (f *Fake) enter(ctx context.Context, method string) error {
+	f.mutex.Lock()
+	f.calls[method]++
+	latency, err := f.latency[method], f.errors[method]
+	f.mutex.Unlock()
+	if latency > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(latency):
+		}
+	}
+	return err
+}
+
+func (f *Fake) bucket(name string) (*FakeBucket, error) {
+	bucket, ok := f.buckets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFakeNoSuchBucket, name)
+	}
+	return bucket, nil
+}
+
+func (f *Fake) BucketCreate(ctx context.Context, name string, objectLocking bool) error {
+	if err := f.enter(ctx, "BucketCreate"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.buckets[name]; ok {
+		return fmt.Errorf("bucket %s already exists", name)
+	}
+	f.buckets[name] = &FakeBucket{ObjectLocking: objectLocking, Policy: v1alpha1.PolicyPrivate}
+	return nil
+}
+
+func (f *Fake) BucketDelete(ctx context.Context, name string) error {
+	if err := f.enter(ctx, "BucketDelete"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, ok := f.buckets[name]
+	if !ok {
+		return nil
+	}
+	if bucket.Objects > 0 || bucket.Retained > 0 {
+		return ErrBucketNotEmpty
+	}
+	delete(f.buckets, name)
+	return nil
+}
+
+func (f *Fake) BucketPurge(ctx context.Context, name string, limit int) (int, error) {
+	if err := f.enter(ctx, "BucketPurge"); err != nil {
+		return 0, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, err := f.bucket(name)
+	if err != nil {
+		return 0, err
+	}
+	removed := min(bucket.Objects, limit)
+	bucket.Objects -= removed
+	if bucket.Retained > 0 {
+		return removed, ErrObjectsRetained
+	}
+	return removed, nil
+}
+
+func (f *Fake) BucketClaim(ctx context.Context, name, owner string, adopt bool) (bool, error) {
+	if err := f.enter(ctx, "BucketClaim"); err != nil {
+		return false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, ok := f.buckets[name]
+	if !ok {
+		return false, nil
+	}
+	current := map[string]string{}
+	if bucket.Owner != "" {
+		current[ClaimTag] = bucket.Owner
+	}
+	if claim, err := decideClaim(current, owner, adopt); err != nil || !claim {
+		return false, err
+	}
+	bucket.Owner = owner
+	return true, nil
+}
+
+func (f *Fake) BucketCheckClaim(ctx context.Context, name, owner string, adopt bool) error {
+	if err := f.enter(ctx, "BucketCheckClaim"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, ok := f.buckets[name]
+	if !ok {
+		return nil
+	}
+	current := map[string]string{}
+	if bucket.Owner != "" {
+		current[ClaimTag] = bucket.Owner
+	}
+	_, err := decideClaim(current, owner, adopt)
+	return err
+}
+
+func (f *Fake) BucketRelease(ctx context.Context, name, owner string) error {
+	if err := f.enter(ctx, "BucketRelease"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if bucket, ok := f.buckets[name]; ok && bucket.Owner == owner {
+		bucket.Owner = ""
+	}
+	return nil
+}
+
+func (f *Fake) BucketExists(ctx context.Context, name string) (bool, error) {
+	if err := f.enter(ctx, "BucketExists"); err != nil {
+		return false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	_, ok := f.buckets[name]
+	return ok, nil
+}
+
+func (f *Fake) BucketPolicyReconcile(ctx context.Context, name string, policy BucketPolicy) (bool, error) {
+	if err := f.enter(ctx, "BucketPolicyReconcile"); err != nil {
+		return false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, err := f.bucket(name)
+	if err != nil || bucket.Policy == policy {
+		return false, err
+	}
+	bucket.Policy = policy
+	return true, nil
+}
+
+func (f *Fake) BucketVersioningReconcile(
+	ctx context.Context, name string, versioning *BucketVersioning,
+) (VersioningStatus, bool, error) {
+	if err := f.enter(ctx, "BucketVersioningReconcile"); err != nil {
+		return "", false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, err := f.bucket(name)
+	if err != nil {
+		return "", false, err
+	}
+	var current VersioningStatus
+	if bucket.Versioning != nil {
+		current = bucket.Versioning.Status
+	}
+	if versioning == nil || reflect.DeepEqual(bucket.Versioning, versioning) {
+		return current, false, nil
+	}
+	bucket.Versioning = versioning.DeepCopy()
+	return versioning.Status, true, nil
+}
+
+func (f *Fake) BucketLifecycleReconcile(ctx context.Context, name string, lifecycle *BucketLifecycle) (bool, error) {
+	if err := f.enter(ctx, "BucketLifecycleReconcile"); err != nil {
+		return false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, err := f.bucket(name)
+	if err != nil || lifecycle == nil || reflect.DeepEqual(bucket.Lifecycle, lifecycle) {
+		return false, err
+	}
+	bucket.Lifecycle = lifecycle.DeepCopy()
+	return true, nil
+}
+
+func (f *Fake) BucketQuotaReconcile(ctx context.Context, name string, size uint64) (bool, error) {
+	if err := f.enter(ctx, "BucketQuotaReconcile"); err != nil {
+		return false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, err := f.bucket(name)
+	if err != nil || bucket.Quota == size {
+		return false, err
+	}
+	bucket.Quota = size
+	return true, nil
+}
+
+func (f *Fake) BucketUsage(ctx context.Context, name string) (uint64, error) {
+	if err := f.enter(ctx, "BucketUsage"); err != nil {
+		return 0, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, err := f.bucket(name)
+	if err != nil {
+		return 0, err
+	}
+	return bucket.Usage.Size, nil
+}
+
+func (f *Fake) BucketsUsage(ctx context.Context) (map[string]UsageInfo, error) {
+	if err := f.enter(ctx, "BucketsUsage"); err != nil {
+		return nil, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	usage := make(map[string]UsageInfo, len(f.buckets))
+	for name, bucket := range f.buckets {
+		usage[name] = bucket.Usage
+	}
+	return usage, nil
+}
+
+func (f *Fake) BucketObjectLockReconcile(ctx context.Context, name string, lock *BucketObjectLock) (bool, error) {
+	if err := f.enter(ctx, "BucketObjectLockReconcile"); err != nil {
+		return false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, err := f.bucket(name)
+	switch {
+	case err != nil:
+		return false, err
+	case lock == nil && bucket.ObjectLocking:
+		return false, fmt.Errorf("%w, its default retention is kept", ErrObjectLockUnmanaged)
+	case lock == nil:
+		return false, nil
+	case !bucket.ObjectLocking:
+		return false, ErrObjectLockNotEnabled
+	case reflect.DeepEqual(bucket.Lock, lock):
+		return false, nil
+	}
+	bucket.Lock = lock.DeepCopy()
+	return true, nil
+}
+
+func (f *Fake) BucketEncryptionReconcile(
+	ctx context.Context, name string, encryption *BucketEncryption,
+) (bool, error) {
+	if err := f.enter(ctx, "BucketEncryptionReconcile"); err != nil {
+		return false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	bucket, err := f.bucket(name)
+	if err != nil || encryption == nil || reflect.DeepEqual(bucket.Encryption, encryption) {
+		return false, err
+	}
+	bucket.Encryption = encryption.DeepCopy()
+	return true, nil
+}
+
+// PolicyReconcile follows the client: the policy is created with its user when nothing
+// is attached to it, otherwise its document is converged and its user replaced when
+// the secret names another one.
+func (f *Fake) PolicyReconcile(ctx context.Context, policy *Policy) (PolicyChanges, error) {
+	changes := PolicyChanges{}
+	if err := f.enter(ctx, "PolicyReconcile"); err != nil {
+		return changes, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	attached := f.attachedUsers(policy.Name)
+	if !decideManaged(f.policies[policy.Name], attached, policy.User.Name) {
+		return changes, fmt.Errorf("%w: %s", ErrPolicyNotManaged, policy.Name)
+	}
+	if len(attached) == 0 {
+		document, err := policyDocument(policy)
+		if err != nil {
+			return changes, err
+		}
+		f.policies[policy.Name] = document
+		f.userCreate(policy)
+		changes.PolicyCreated = true
+		return changes, nil
+	}
+
+	expected, err := decideCannedPolicy(f.policies[policy.Name], policy)
+	if err != nil {
+		return changes, err
+	}
+	if expected != nil {
+		f.policies[policy.Name] = expected
+		changes.PolicyUpdated = true
+	}
+	if user := attached[0]; user != policy.User.Name {
+		f.userDelete(user)
+		f.userCreate(policy)
+		changes.UserRecreated = true
+		return changes, nil
+	}
+	if policy.User.Password != "" {
+		f.users[policy.User.Name].Password = policy.User.Password
+	}
+	return changes, nil
+}
+
+// attachedUsers returns the sorted users attached to the policy.
+func (f *Fake) attachedUsers(policy string) []string {
+	users := []string{}
+	for name, user := range f.users {
+		if slices.Contains(user.Policies, policy) {
+			users = append(users, name)
+		}
+	}
+	slices.Sort(users)
+	return users
+}
+
+func (f *Fake) userCreate(policy *Policy) {
+	user, ok := f.users[policy.User.Name]
+	if !ok {
+		user = &FakeUser{}
+		f.users[policy.User.Name] = user
+	}
+	user.Password = policy.User.Password
+	if !slices.Contains(user.Policies, policy.Name) {
+		user.Policies = append(user.Policies, policy.Name)
+	}
+}
+
+func (f *Fake) userDelete(name string) {
+	delete(f.users, name)
+	for accessKey, account := range f.serviceAccounts {
+		if account.Parent == name {
+			delete(f.serviceAccounts, accessKey)
+		}
+	}
+	// the temporary credentials are revoked along with their user
+	for accessKey, session := range f.sessions {
+		if session.Parent == name {
+			delete(f.sessions, accessKey)
+		}
+	}
+	for _, group := range f.groups {
+		group.Members = slices.DeleteFunc(group.Members, func(member string) bool { return member == name })
+	}
+}
+
+func (f *Fake) PolicyDelete(ctx context.Context, name, user string) error {
+	if err := f.enter(ctx, "PolicyDelete"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !decideManaged(f.policies[name], f.attachedUsers(name), user) {
+		return nil
+	}
+	for _, user := range f.attachedUsers(name) {
+		f.userDelete(user)
+	}
+	for _, group := range f.groups {
+		group.Policies = slices.DeleteFunc(group.Policies, func(policy string) bool { return policy == name })
+	}
+	f.ldapDetach(name, bound(f.ldapUsers, name), bound(f.ldapGroups, name))
+	delete(f.policies, name)
+	return nil
+}
+
+// IdentityPolicyReconcile follows the client, the LDAP attachments are decided from the same mappings.
+func (f *Fake) IdentityPolicyReconcile(ctx context.Context, policy *Policy, identities Identities) (PolicyChanges, error) {
+	changes := PolicyChanges{}
+	if err := f.enter(ctx, "IdentityPolicyReconcile"); err != nil {
+		return changes, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	current, ok := f.policies[policy.Name]
+	if !decideManaged(current, nil, "") {
+		return changes, fmt.Errorf("%w: %s", ErrPolicyNotManaged, policy.Name)
+	}
+	changes.PolicyCreated = !ok
+	expected, err := decideCannedPolicy(current, policy)
+	if err != nil {
+		return changes, err
+	}
+	if expected != nil {
+		f.policies[policy.Name] = expected
+		changes.PolicyUpdated = ok
+	}
+	for _, user := range f.attachedUsers(policy.Name) {
+		f.users[user].Policies = slices.DeleteFunc(f.users[user].Policies, func(p string) bool {
+			return p == policy.Name
+		})
+		changes.UsersDetached = true
+	}
+
+	update := decideIdentities(bound(f.ldapUsers, policy.Name), bound(f.ldapGroups, policy.Name), identities)
+	for _, user := range update.attachUsers {
+		f.ldapUsers[user] = append(f.ldapUsers[user], policy.Name)
+	}
+	for _, group := range update.attachGroups {
+		f.ldapGroups[group] = append(f.ldapGroups[group], policy.Name)
+	}
+	f.ldapDetach(policy.Name, update.detachUsers, update.detachGroups)
+	changes.IdentitiesUpdated = !update.empty()
+	return changes, nil
+}
+
+func (f *Fake) ldapDetach(policy string, users, groups []string) {
+	isPolicy := func(name string) bool { return name == policy }
+	for _, user := range users {
+		f.ldapUsers[user] = slices.DeleteFunc(f.ldapUsers[user], isPolicy)
+	}
+	for _, group := range groups {
+		f.ldapGroups[group] = slices.DeleteFunc(f.ldapGroups[group], isPolicy)
+	}
+}
+
+func (f *Fake) ServiceAccountReconcile(ctx context.Context, account ServiceAccount) (bool, error) {
+	if err := f.enter(ctx, "ServiceAccountReconcile"); err != nil {
+		return false, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if current, ok := f.serviceAccounts[account.AccessKey]; ok && current.Parent != account.Parent {
+		return false, fmt.Errorf("%w: %s", ErrServiceAccountNotOwned, account.AccessKey)
+	} else if ok {
+		if account.Name == "" {
+			account.Name = current.Name
+		}
+		// the secret key is only set on creation
+		account.SecretKey = current.SecretKey
+		if current.Name == account.Name && current.Disabled == account.Disabled &&
+			equalExpiration(current.Expiration, account.Expiration) {
+			return false, nil
+		}
+		f.serviceAccounts[account.AccessKey] = account
+		return true, nil
+	}
+	if _, ok := f.users[account.Parent]; !ok {
+		return false, fmt.Errorf("parent user %s does not exist", account.Parent)
+	}
+	f.serviceAccounts[account.AccessKey] = account
+	return true, nil
+}
+
+func equalExpiration(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func (f *Fake) ServiceAccountDelete(ctx context.Context, accessKey, parent string) error {
+	if err := f.enter(ctx, "ServiceAccountDelete"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if current, ok := f.serviceAccounts[accessKey]; ok && current.Parent != parent {
+		return fmt.Errorf("%w: %s", ErrServiceAccountNotOwned, accessKey)
+	}
+	delete(f.serviceAccounts, accessKey)
+	return nil
+}
+
+func (f *Fake) UserDelete(ctx context.Context, name string) error {
+	if err := f.enter(ctx, "UserDelete"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.userDelete(name)
+	return nil
+}
+
+func (f *Fake) CannedPolicyDelete(ctx context.Context, name string) error {
+	if err := f.enter(ctx, "CannedPolicyDelete"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	current, ok := f.policies[name]
+	if !ok || !isManagedPolicy(current) {
+		return nil
+	}
+	isPolicy := func(policy string) bool { return policy == name }
+	for _, user := range f.attachedUsers(name) {
+		f.users[user].Policies = slices.DeleteFunc(f.users[user].Policies, isPolicy)
+	}
+	for _, group := range f.groups {
+		group.Policies = slices.DeleteFunc(group.Policies, isPolicy)
+	}
+	f.ldapDetach(name, bound(f.ldapUsers, name), bound(f.ldapGroups, name))
+	delete(f.policies, name)
+	return nil
+}
+
+func (f *Fake) AssumeRole(
+	ctx context.Context, user string, duration time.Duration,
+) (TemporaryCredentials, error) {
+	if err := f.enter(ctx, "AssumeRole"); err != nil {
+		return TemporaryCredentials{}, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	current, ok := f.users[user]
+	if !ok {
+		return TemporaryCredentials{}, ErrFakeAccessDenied
+	}
+	password, err := GenerateSecretKey(0, nil)
+	if err != nil {
+		return TemporaryCredentials{}, err
+	}
+	current.Password = string(password)
+	accessKey, err := GenerateAccessKey(0, nil)
+	if err != nil {
+		return TemporaryCredentials{}, err
+	}
+	secretKey, err := GenerateSecretKey(0, nil)
+	if err != nil {
+		return TemporaryCredentials{}, err
+	}
+	credentials := TemporaryCredentials{
+		AccessKey: string(accessKey), SecretKey: string(secretKey),
+		SessionToken: "token-" + string(accessKey), Expiration: time.Now().Add(duration),
+	}
+	f.sessions[credentials.AccessKey] = FakeSession{Parent: user, TemporaryCredentials: credentials}
+	return credentials, nil
+}
+
+// GroupReconcile follows the client, the changes are decided from the same description.
+func (f *Fake) GroupReconcile(ctx context.Context, group Group) (GroupChanges, error) {
+	changes := GroupChanges{}
+	if err := f.enter(ctx, "GroupReconcile"); err != nil {
+		return changes, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	current, ok := f.groups[group.Name]
+	if !ok {
+		f.groups[group.Name] = &FakeGroup{
+			Members: slices.Clone(group.Members), Policies: slices.Clone(group.Policies),
+		}
+		changes.Created = true
+		return changes, nil
+	}
+	update := decideGroup(&madmin.GroupDesc{
+		Members: current.Members, Policy: strings.Join(current.Policies, ","),
+		Status: string(statusOf(current.Disabled)),
+	}, group)
+	current.Members = append(slices.DeleteFunc(current.Members, func(member string) bool {
+		return slices.Contains(update.remove, member)
+	}), update.add...)
+	current.Policies = append(slices.DeleteFunc(current.Policies, func(policy string) bool {
+		return slices.Contains(update.detach, policy)
+	}), update.attach...)
+	current.Disabled = false
+	changes.Enabled = update.enable
+	changes.MembersUpdated = len(update.add) > 0 || len(update.remove) > 0
+	changes.PoliciesUpdated = len(update.attach) > 0 || len(update.detach) > 0
+	return changes, nil
+}
+
+func statusOf(disabled bool) madmin.GroupStatus {
+	if disabled {
+		return madmin.GroupDisabled
+	}
+	return madmin.GroupEnabled
+}
+
+func (f *Fake) GroupDelete(ctx context.Context, name string) error {
+	if err := f.enter(ctx, "GroupDelete"); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.groups, name)
+	return nil
+}
+
+func (f *Fake) Inventory(ctx context.Context) (Inventory, error) {
+	if err := f.enter(ctx, "Inventory"); err != nil {
+		return Inventory{}, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	inventory := Inventory{
+		Buckets:   make(map[string]string, len(f.buckets)),
+		Policies:  make(map[string]bool, len(f.policies)),
+		Users:     make(map[string][]string, len(f.users)),
+		LDAPBound: map[string]bool{},
+	}
+	for name, bucket := range f.buckets {
+		inventory.Buckets[name] = bucket.Owner
+	}
+	for name, document := range f.policies {
+		inventory.Policies[name] = isManagedPolicy(document)
+	}
+	for name, user := range f.users {
+		inventory.Users[name] = slices.Clone(user.Policies)
+	}
+	for _, name := range slices.Sorted(maps.Keys(f.groups)) {
+		group := f.groups[name]
+		inventory.Groups = append(inventory.Groups, Group{
+			Name: name, Members: slices.Clone(group.Members), Policies: slices.Clone(group.Policies),
+		})
+	}
+	for _, bindings := range []map[string][]string{f.ldapUsers, f.ldapGroups} {
+		for _, policies := range bindings {
+			for _, policy := range policies {
+				inventory.LDAPBound[policy] = true
+			}
+		}
+	}
+	return inventory, nil
+}
+
+func (f *Fake) Health(ctx context.Context) error {
+	return f.enter(ctx, "Health")
+}
+
+func (f *Fake) Endpoint() Endpoint {
+	return Endpoint{URL: "http://minio.fake:9000", Host: "minio.fake:9000", Region: defaultLocation}
+}
@@ -0,0 +1,283 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/IxDay/api/v1alpha1"
+)
+
+func TestFake_policyLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	require.NoError(t, fake.BucketCreate(ctx, "bucket", false))
+
+	policy := NewDefaultPolicy("bucket")
+	require.NoError(t, policy.SetUser([]byte("USER"), []byte("password")))
+	changes, err := fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{PolicyCreated: true}, changes)
+	user, ok := fake.User("USER")
+	require.True(t, ok)
+	assert.Equal(t, FakeUser{Password: "password", Policies: []string{"bucket"}}, user)
+
+	changes, err = fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{}, changes)
+
+	// a policy removed behind the controller is restored
+	fake.RemoveCannedPolicy("bucket")
+	changes, err = fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{PolicyUpdated: true}, changes)
+
+	require.NoError(t, policy.SetUser([]byte("OTHER"), []byte("password")))
+	changes, err = fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{UserRecreated: true}, changes)
+	assert.Equal(t, []string{"OTHER"}, fake.Users())
+
+	require.NoError(t, fake.PolicyDelete(ctx, "bucket", "OTHER"))
+	assert.Empty(t, fake.Users())
+	_, ok = fake.CannedPolicy("bucket")
+	assert.False(t, ok)
+}
+
+func TestFake_policyDocument(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	policy := &Policy{Name: "bucket.reader", Bucket: "bucket"}
+	require.NoError(t, policy.SetUser([]byte("USER"), []byte("password")))
+	require.NoError(t, policy.SetPolicy([]v1alpha1.Statement{{Effect: "Allow", Actions: []string{"s3:ListBucket"}}}))
+	_, err := fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+
+	// editing the statements or the document of an existing policy converges the canned policy
+	require.NoError(t, policy.SetPolicy([]v1alpha1.Statement{{Effect: "Allow", Actions: []string{"s3:GetObject"}}}))
+	changes, err := fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{PolicyUpdated: true}, changes)
+	document, _ := fake.CannedPolicy("bucket.reader")
+	assert.Contains(t, string(document), "s3:GetObject")
+	assert.NotContains(t, string(document), "s3:ListBucket")
+
+	require.NoError(t, policy.SetDocument(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow",`+
+		`"Action":["s3:PutObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`, false))
+	changes, err = fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{PolicyUpdated: true}, changes)
+	document, _ = fake.CannedPolicy("bucket.reader")
+	assert.Contains(t, string(document), "s3:PutObject")
+
+	changes, err = fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{}, changes, "an unchanged document is not pushed again")
+}
+
+func TestFake_unmanagedPolicy(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	document := []byte(`{"Version":"2012-10-17","Statement":[]}`)
+	fake.SetCannedPolicy("bucket", document)
+
+	policy := NewDefaultPolicy("bucket")
+	require.NoError(t, policy.SetUser([]byte("USER"), []byte("password")))
+	_, err := fake.PolicyReconcile(ctx, policy)
+	assert.ErrorIs(t, err, ErrPolicyNotManaged)
+	_, err = fake.IdentityPolicyReconcile(ctx, policy, Identities{})
+	assert.ErrorIs(t, err, ErrPolicyNotManaged)
+	current, _ := fake.CannedPolicy("bucket")
+	assert.Equal(t, document, current, "the canned policy is left untouched")
+	assert.Empty(t, fake.Users())
+	require.NoError(t, fake.PolicyDelete(ctx, "bucket", "USER"))
+	_, ok := fake.CannedPolicy("bucket")
+	assert.True(t, ok, "the canned policy is never deleted")
+
+	// a canned policy created before the marker was recorded is deleted with the user of the resource
+	fake.userCreate(policy)
+	require.NoError(t, fake.PolicyDelete(ctx, "bucket", "OTHER"))
+	_, ok = fake.CannedPolicy("bucket")
+	assert.True(t, ok, "the canned policy is not attached to OTHER")
+	require.NoError(t, fake.PolicyDelete(ctx, "bucket", "USER"))
+	_, ok = fake.CannedPolicy("bucket")
+	assert.False(t, ok)
+	assert.Empty(t, fake.Users())
+}
+
+func TestFake_cannedPolicyDelete(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	require.NoError(t, fake.BucketCreate(ctx, "bucket", false))
+	policy := NewDefaultPolicy("bucket")
+	require.NoError(t, policy.SetUser([]byte("USER"), []byte("password")))
+	_, err := fake.PolicyReconcile(ctx, policy)
+	require.NoError(t, err)
+	_, err = fake.GroupReconcile(ctx, Group{Name: "team", Members: []string{"USER"}, Policies: []string{"bucket"}})
+	require.NoError(t, err)
+
+	inventory, err := fake.Inventory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"bucket": true}, inventory.Policies)
+	assert.Equal(t, []Group{{Name: "team", Members: []string{"USER"}, Policies: []string{"bucket"}}}, inventory.Groups)
+
+	require.NoError(t, fake.CannedPolicyDelete(ctx, "bucket"))
+	_, ok := fake.CannedPolicy("bucket")
+	assert.False(t, ok)
+	user, ok := fake.User("USER")
+	require.True(t, ok, "the users of the canned policy are kept")
+	assert.Empty(t, user.Policies)
+	group, _ := fake.Group("team")
+	assert.Empty(t, group.Policies)
+}
+
+func TestFake_identityPolicy(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	require.NoError(t, fake.BucketCreate(ctx, "bucket", false))
+
+	policy := NewDefaultPolicy("bucket")
+	identities := Identities{LDAPUsers: []string{dnAlice}, LDAPGroups: []string{dnAdmins}}
+	changes, err := fake.IdentityPolicyReconcile(ctx, policy, identities)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{PolicyCreated: true, IdentitiesUpdated: true}, changes)
+	assert.Empty(t, fake.Users())
+	assert.Equal(t, identities, fake.LDAPBindings("bucket"))
+
+	changes, err = fake.IdentityPolicyReconcile(ctx, policy, identities)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{}, changes)
+
+	// only the identities are granted the policy
+	require.NoError(t, policy.SetUser([]byte("USER"), []byte("password")))
+	fake.userCreate(policy)
+	changes, err = fake.IdentityPolicyReconcile(ctx, policy, Identities{LDAPUsers: []string{dnBob}})
+	require.NoError(t, err)
+	assert.Equal(t, PolicyChanges{UsersDetached: true, IdentitiesUpdated: true}, changes)
+	user, _ := fake.User("USER")
+	assert.Empty(t, user.Policies)
+	assert.Equal(t, Identities{LDAPUsers: []string{dnBob}, LDAPGroups: []string{}}, fake.LDAPBindings("bucket"))
+
+	require.NoError(t, fake.PolicyDelete(ctx, "bucket", ""))
+	assert.Equal(t, Identities{LDAPUsers: []string{}, LDAPGroups: []string{}}, fake.LDAPBindings("bucket"))
+}
+
+func TestFake_foreignServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	for _, user := range []string{"alice", "mallory"} {
+		policy := NewDefaultPolicy(user)
+		require.NoError(t, policy.SetUser([]byte(user), []byte("password")))
+		_, err := fake.PolicyReconcile(ctx, policy)
+		require.NoError(t, err)
+	}
+	_, err := fake.ServiceAccountReconcile(ctx, ServiceAccount{Parent: "mallory", AccessKey: "KEY"})
+	require.NoError(t, err)
+
+	_, err = fake.ServiceAccountReconcile(ctx, ServiceAccount{Parent: "alice", AccessKey: "KEY"})
+	assert.ErrorIs(t, err, ErrServiceAccountNotOwned)
+	assert.ErrorIs(t, fake.ServiceAccountDelete(ctx, "KEY", "alice"), ErrServiceAccountNotOwned)
+	account, ok := fake.ServiceAccount("KEY")
+	require.True(t, ok)
+	assert.Equal(t, "mallory", account.Parent, "the account of another user is left untouched")
+
+	require.NoError(t, fake.ServiceAccountDelete(ctx, "KEY", "mallory"))
+	require.NoError(t, fake.ServiceAccountDelete(ctx, "KEY", "alice"), "a missing account is not an error")
+}
+
+func TestFake_buckets(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	require.NoError(t, fake.BucketCreate(ctx, "bucket", false))
+	assert.Error(t, fake.BucketCreate(ctx, "bucket", false))
+
+	claimed, err := fake.BucketClaim(ctx, "bucket", "default/bucket", true)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	_, err = fake.BucketClaim(ctx, "bucket", "default/other", true)
+	assert.ErrorIs(t, err, ErrBucketClaimed)
+	assert.ErrorIs(t, fake.BucketCheckClaim(ctx, "bucket", "default/other", true), ErrBucketClaimed)
+	assert.NoError(t, fake.BucketCheckClaim(ctx, "missing", "default/other", false))
+	fake.SetBucket("free", FakeBucket{})
+	require.NoError(t, fake.BucketCheckClaim(ctx, "free", "default/free", true))
+	free, _ := fake.Bucket("free")
+	assert.Empty(t, free.Owner, "checking a claim never writes it")
+	require.NoError(t, fake.BucketDelete(ctx, "free"))
+
+	changed, err := fake.BucketPolicyReconcile(ctx, "bucket", v1alpha1.PolicyPrivate)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	_, err = fake.BucketObjectLockReconcile(ctx, "bucket", &BucketObjectLock{})
+	assert.ErrorIs(t, err, ErrObjectLockNotEnabled)
+	_, err = fake.BucketQuotaReconcile(ctx, "missing", 1)
+	assert.ErrorIs(t, err, ErrFakeNoSuchBucket)
+
+	fake.SetBucket("bucket", FakeBucket{Owner: "default/bucket", Objects: 3})
+	assert.ErrorIs(t, fake.BucketDelete(ctx, "bucket"), ErrBucketNotEmpty)
+	removed, err := fake.BucketPurge(ctx, "bucket", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	removed, err = fake.BucketPurge(ctx, "bucket", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	require.NoError(t, fake.BucketDelete(ctx, "bucket"))
+	assert.Empty(t, fake.Buckets())
+
+	fake.SetBucket("retained", FakeBucket{Objects: 1, Retained: 1})
+	removed, err = fake.BucketPurge(ctx, "retained", 2)
+	assert.ErrorIs(t, err, ErrObjectsRetained)
+	assert.Equal(t, 1, removed)
+	assert.ErrorIs(t, fake.BucketDelete(ctx, "retained"), ErrBucketNotEmpty)
+}
+
+func TestFake_groups(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+
+	changes, err := fake.GroupReconcile(ctx, Group{Name: "team", Members: []string{"alice"}, Policies: []string{"read"}})
+	require.NoError(t, err)
+	assert.Equal(t, GroupChanges{Created: true}, changes)
+
+	fake.SetGroup("team", FakeGroup{Members: []string{"alice", "mallory"}, Policies: []string{"read"}, Disabled: true})
+	changes, err = fake.GroupReconcile(ctx, Group{Name: "team", Members: []string{"alice"}, Policies: []string{"write"}})
+	require.NoError(t, err)
+	assert.Equal(t, GroupChanges{Enabled: true, MembersUpdated: true, PoliciesUpdated: true}, changes)
+	group, _ := fake.Group("team")
+	assert.Equal(t, FakeGroup{Members: []string{"alice"}, Policies: []string{"write"}}, group)
+
+	require.NoError(t, fake.BucketCreate(ctx, "bucket", false))
+	_, err = fake.PolicyReconcile(ctx, NewDefaultPolicy("bucket"))
+	require.NoError(t, err)
+	_, err = fake.GroupReconcile(ctx, Group{Name: "team", Members: []string{"alice"}, Policies: []string{"write", "bucket"}})
+	require.NoError(t, err)
+	require.NoError(t, fake.PolicyDelete(ctx, "bucket", ""))
+	group, _ = fake.Group("team")
+	assert.Equal(t, []string{"write"}, group.Policies, "a deleted policy is detached from the groups")
+
+	require.NoError(t, fake.GroupDelete(ctx, "team"))
+	_, ok := fake.Group("team")
+	assert.False(t, ok)
+}
+
+func TestFake_injection(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	failure := errors.New("unreachable")
+
+	fake.SetError("BucketExists", failure)
+	_, err := fake.BucketExists(ctx, "bucket")
+	assert.ErrorIs(t, err, failure)
+	fake.SetError("BucketExists", nil)
+	_, err = fake.BucketExists(ctx, "bucket")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fake.Calls("BucketExists"))
+
+	fake.SetLatency("Health", time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, fake.Health(ctx), context.DeadlineExceeded)
+}
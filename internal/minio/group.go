@@ -0,0 +1,144 @@
+package minio
+
+import (
+	"context"
+	"slices"
+
+	"github.com/minio/madmin-go/v3"
+)
+
+const errNoSuchGroup = "XMinioAdminNoSuchGroup"
+
+// Group is a MinIO group, its members are granted the canned policies attached to it.
+type Group struct {
+	Name              string
+	Members, Policies []string
+}
+
+// GroupChanges tells what GroupReconcile changed in MinIO.
+type GroupChanges struct {
+	// Created is set when the group did not exist
+	Created bool
+	// Enabled is set when a disabled group was enabled again
+	Enabled bool
+	// MembersUpdated is set when users were added to or removed from an existing group
+	MembersUpdated bool
+	// PoliciesUpdated is set when policies were attached to or detached from an existing group
+	PoliciesUpdated bool
+}
+
+// groupUpdate is the difference between a group in MinIO and the wanted one.
+type groupUpdate struct {
+	add, remove, attach, detach []string
+	enable                      bool
+}
+
+// GroupReconcile creates the group when missing, then converges its status, its members
+// and the policies attached to it.
+func (c *client) GroupReconcile(ctx context.Context, group Group) (GroupChanges, error) {
+	changes := GroupChanges{}
+	current, err := c.GetGroupDescription(ctx, group.Name)
+	if madmin.ToErrorResponse(err).Code == errNoSuchGroup {
+		current, err = nil, nil
+		changes.Created = true
+	}
+	if err != nil {
+		return changes, err
+	}
+	update := decideGroup(current, group)
+
+	// adding members creates the group, even when there are none to add
+	if len(update.add) > 0 || changes.Created {
+		if err := c.UpdateGroupMembers(ctx, madmin.GroupAddRemove{
+			Group: group.Name, Members: update.add, Status: madmin.GroupEnabled,
+		}); err != nil {
+			return changes, err
+		}
+		changes.MembersUpdated = !changes.Created
+	}
+	if len(update.remove) > 0 {
+		if err := c.UpdateGroupMembers(ctx, madmin.GroupAddRemove{
+			Group: group.Name, Members: update.remove, IsRemove: true,
+		}); err != nil {
+			return changes, err
+		}
+		changes.MembersUpdated = true
+	}
+	if update.enable {
+		if err := c.SetGroupStatus(ctx, group.Name, madmin.GroupEnabled); err != nil {
+			return changes, err
+		}
+		changes.Enabled = true
+	}
+	if len(update.attach) > 0 {
+		_, err := c.AttachPolicy(ctx, madmin.PolicyAssociationReq{Policies: update.attach, Group: group.Name})
+		if err != nil && madmin.ToErrorResponse(err).Code != errPolicyAlreadyApplied {
+			return changes, err
+		}
+		changes.PoliciesUpdated = !changes.Created
+	}
+	if len(update.detach) > 0 {
+		_, err := c.DetachPolicy(ctx, madmin.PolicyAssociationReq{Policies: update.detach, Group: group.Name})
+		if err != nil && madmin.ToErrorResponse(err).Code != errPolicyAlreadyApplied {
+			return changes, err
+		}
+		changes.PoliciesUpdated = true
+	}
+	return changes, nil
+}
+
+// decideGroup returns the changes converging the current group, nil when it does not exist yet.
+func decideGroup(current *madmin.GroupDesc, wanted Group) groupUpdate {
+	if current == nil {
+		return groupUpdate{add: wanted.Members, attach: wanted.Policies}
+	}
+	policies := splitPolicies(current.Policy)
+	return groupUpdate{
+		add:    missingFrom(wanted.Members, current.Members),
+		remove: missingFrom(current.Members, wanted.Members),
+		attach: missingFrom(wanted.Policies, policies),
+		detach: missingFrom(policies, wanted.Policies),
+		enable: current.Status != string(madmin.GroupEnabled),
+	}
+}
+
+// missingFrom returns the values absent from others, in their original order.
+func missingFrom(values, others []string) []string {
+	var missing []string
+	for _, value := range values {
+		if !slices.Contains(others, value) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+// GroupDelete detaches the policies and removes the members of the group before
+// removing it, a missing group is not an error.
+func (c *client) GroupDelete(ctx context.Context, name string) error {
+	current, err := c.GetGroupDescription(ctx, name)
+	if madmin.ToErrorResponse(err).Code == errNoSuchGroup {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if policies := splitPolicies(current.Policy); len(policies) > 0 {
+		_, err := c.DetachPolicy(ctx, madmin.PolicyAssociationReq{Policies: policies, Group: name})
+		if err != nil && madmin.ToErrorResponse(err).Code != errPolicyAlreadyApplied {
+			return err
+		}
+	}
+	if len(current.Members) > 0 {
+		if err := c.UpdateGroupMembers(ctx, madmin.GroupAddRemove{
+			Group: name, Members: current.Members, IsRemove: true,
+		}); err != nil {
+			return err
+		}
+	}
+	// removing no member from an empty group deletes it
+	err = c.UpdateGroupMembers(ctx, madmin.GroupAddRemove{Group: name, IsRemove: true})
+	if madmin.ToErrorResponse(err).Code == errNoSuchGroup {
+		return nil
+	}
+	return err
+}
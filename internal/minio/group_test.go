@@ -0,0 +1,52 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var decideGroupEntries = []struct {
+	name     string
+	current  *madmin.GroupDesc
+	wanted   Group
+	expected groupUpdate
+}{
+	{
+		name:     "missing",
+		wanted:   Group{Members: []string{"alice"}, Policies: []string{"read"}},
+		expected: groupUpdate{add: []string{"alice"}, attach: []string{"read"}},
+	},
+	{
+		name:    "in sync",
+		current: &madmin.GroupDesc{Status: "enabled", Members: []string{"bob", "alice"}, Policy: "write,read"},
+		wanted:  Group{Members: []string{"alice", "bob"}, Policies: []string{"read", "write"}},
+	},
+	{
+		name:     "members changed",
+		current:  &madmin.GroupDesc{Status: "enabled", Members: []string{"alice", "carol"}},
+		wanted:   Group{Members: []string{"alice", "bob"}},
+		expected: groupUpdate{add: []string{"bob"}, remove: []string{"carol"}},
+	},
+	{
+		name:     "policies changed",
+		current:  &madmin.GroupDesc{Status: "enabled", Policy: "read,admin"},
+		wanted:   Group{Policies: []string{"read", "write"}},
+		expected: groupUpdate{attach: []string{"write"}, detach: []string{"admin"}},
+	},
+	{
+		name:     "disabled",
+		current:  &madmin.GroupDesc{Status: "disabled", Members: []string{"alice"}},
+		wanted:   Group{Members: []string{"alice"}},
+		expected: groupUpdate{enable: true},
+	},
+}
+
+func Test_decideGroup(t *testing.T) {
+	for _, entry := range decideGroupEntries {
+		t.Run(entry.name, func(t *testing.T) {
+			assert.Equal(t, entry.expected, decideGroup(entry.current, entry.wanted))
+		})
+	}
+}
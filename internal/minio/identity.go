@@ -0,0 +1,155 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/minio/madmin-go/v3"
+)
+
+const errLDAPNotEnabled = "XMinioLDAPNotEnabled"
+
+// Identities are the LDAP users and groups, by distinguished name, a canned policy is attached to.
+type Identities struct {
+	LDAPUsers, LDAPGroups []string
+}
+
+// identitiesUpdate is the difference between the LDAP attachments of a policy and the wanted ones.
+type identitiesUpdate struct {
+	attachUsers, detachUsers, attachGroups, detachGroups []string
+}
+
+func (u identitiesUpdate) empty() bool {
+	return len(u.attachUsers)+len(u.detachUsers)+len(u.attachGroups)+len(u.detachGroups) == 0
+}
+
+// IdentityPolicyReconcile converges the canned policy of a policy bound to external identities: it
+// is attached to the wanted LDAP users and groups and detached from the other ones. No user is
+// generated, the policy is detached from the MinIO users it was attached to. Like PolicyReconcile,
+// a canned policy which was not created by the controller is never taken over.
+func (c *client) IdentityPolicyReconcile(
+	ctx context.Context, policy *Policy, identities Identities,
+) (PolicyChanges, error) {
+	changes := PolicyChanges{}
+	current, err := c.cannedPolicy(ctx, policy.Name)
+	if err != nil {
+		return changes, err
+	}
+	if !decideManaged(current, nil, "") {
+		return changes, fmt.Errorf("%w: %s", ErrPolicyNotManaged, policy.Name)
+	}
+	changes.PolicyCreated = current == nil
+	expected, err := decideCannedPolicy(current, policy)
+	if err != nil {
+		return changes, err
+	}
+	if expected != nil {
+		if err := c.AddCannedPolicy(ctx, policy.Name, expected); err != nil {
+			return changes, err
+		}
+		changes.PolicyUpdated = !changes.PolicyCreated
+	}
+
+	entities, err := c.GetPolicyEntities(ctx, madmin.PolicyEntitiesQuery{Policy: []string{policy.Name}})
+	if err != nil {
+		return changes, err
+	}
+	for _, mapping := range entities.PolicyMappings {
+		for _, user := range mapping.Users {
+			_, err := c.DetachPolicy(ctx, madmin.PolicyAssociationReq{Policies: []string{policy.Name}, User: user})
+			if err != nil && madmin.ToErrorResponse(err).Code != errPolicyAlreadyApplied {
+				return changes, err
+			}
+			changes.UsersDetached = true
+		}
+	}
+
+	ldapUsers, ldapGroups, err := c.ldapEntities(ctx, policy.Name)
+	if madmin.ToErrorResponse(err).Code == errLDAPNotEnabled &&
+		len(identities.LDAPUsers) == 0 && len(identities.LDAPGroups) == 0 {
+		// nothing can be attached without LDAP, a bare OIDC binding
+		return changes, nil
+	} else if err != nil {
+		return changes, err
+	}
+	update := decideIdentities(ldapUsers, ldapGroups, identities)
+	for _, user := range update.attachUsers {
+		if err := c.ldapAttach(ctx, madmin.PolicyAssociationReq{Policies: []string{policy.Name}, User: user}); err != nil {
+			return changes, err
+		}
+	}
+	for _, group := range update.attachGroups {
+		if err := c.ldapAttach(ctx, madmin.PolicyAssociationReq{Policies: []string{policy.Name}, Group: group}); err != nil {
+			return changes, err
+		}
+	}
+	if err := c.ldapDetach(ctx, policy.Name, update.detachUsers, update.detachGroups); err != nil {
+		return changes, err
+	}
+	changes.IdentitiesUpdated = !update.empty()
+	return changes, nil
+}
+
+// decideIdentities returns the attachments converging the current LDAP users and groups of a policy.
+// Distinguished names are compared regardless of their case, as normalized by MinIO.
+func decideIdentities(users, groups []string, wanted Identities) identitiesUpdate {
+	return identitiesUpdate{
+		attachUsers:  missingDNs(wanted.LDAPUsers, users),
+		detachUsers:  missingDNs(users, wanted.LDAPUsers),
+		attachGroups: missingDNs(wanted.LDAPGroups, groups),
+		detachGroups: missingDNs(groups, wanted.LDAPGroups),
+	}
+}
+
+// missingDNs returns the distinguished names absent from others, in their original order.
+func missingDNs(values, others []string) []string {
+	var missing []string
+	for _, value := range values {
+		if !slices.ContainsFunc(others, func(other string) bool { return strings.EqualFold(value, other) }) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+// ldapEntities returns the LDAP users and groups the policy is attached to.
+func (c *client) ldapEntities(ctx context.Context, policy string) ([]string, []string, error) {
+	result, err := c.GetLDAPPolicyEntities(ctx, madmin.PolicyEntitiesQuery{Policy: []string{policy}})
+	if err != nil {
+		return nil, nil, err
+	}
+	var users, groups []string
+	for _, mapping := range result.PolicyMappings {
+		users = append(users, mapping.Users...)
+		groups = append(groups, mapping.Groups...)
+	}
+	return users, groups, nil
+}
+
+func (c *client) ldapAttach(ctx context.Context, association madmin.PolicyAssociationReq) error {
+	_, err := c.AttachPolicyLDAP(ctx, association)
+	if err != nil && madmin.ToErrorResponse(err).Code != errPolicyAlreadyApplied {
+		return err
+	}
+	return nil
+}
+
+// ldapDetach detaches the policy from the LDAP users and groups.
+func (c *client) ldapDetach(ctx context.Context, policy string, users, groups []string) error {
+	associations := []madmin.PolicyAssociationReq{}
+	for _, user := range users {
+		associations = append(associations, madmin.PolicyAssociationReq{Policies: []string{policy}, User: user})
+	}
+	for _, group := range groups {
+		associations = append(associations, madmin.PolicyAssociationReq{Policies: []string{policy}, Group: group})
+	}
+	for _, association := range associations {
+		_, err := c.DetachPolicyLDAP(ctx, association)
+		if err != nil && madmin.ToErrorResponse(err).Code != errPolicyAlreadyApplied {
+			return err
+		}
+	}
+	return nil
+}
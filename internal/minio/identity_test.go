@@ -0,0 +1,57 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dnAlice  = "uid=alice,ou=people,dc=example,dc=org"
+	dnBob    = "uid=bob,ou=people,dc=example,dc=org"
+	dnAdmins = "cn=admins,ou=groups,dc=example,dc=org"
+)
+
+var decideIdentitiesEntries = []struct {
+	name          string
+	users, groups []string
+	wanted        Identities
+	expected      identitiesUpdate
+}{
+	{
+		name:     "unbound",
+		wanted:   Identities{LDAPUsers: []string{dnAlice}, LDAPGroups: []string{dnAdmins}},
+		expected: identitiesUpdate{attachUsers: []string{dnAlice}, attachGroups: []string{dnAdmins}},
+	},
+	{
+		name:   "in sync",
+		users:  []string{dnAlice},
+		groups: []string{dnAdmins},
+		wanted: Identities{LDAPUsers: []string{dnAlice}, LDAPGroups: []string{dnAdmins}},
+	},
+	{
+		name:   "case insensitive",
+		users:  []string{dnAlice},
+		wanted: Identities{LDAPUsers: []string{"UID=Alice,OU=People,DC=example,DC=org"}},
+	},
+	{
+		name:     "user replaced",
+		users:    []string{dnAlice},
+		wanted:   Identities{LDAPUsers: []string{dnBob}},
+		expected: identitiesUpdate{attachUsers: []string{dnBob}, detachUsers: []string{dnAlice}},
+	},
+	{
+		name:     "all removed",
+		users:    []string{dnAlice},
+		groups:   []string{dnAdmins},
+		expected: identitiesUpdate{detachUsers: []string{dnAlice}, detachGroups: []string{dnAdmins}},
+	},
+}
+
+func Test_decideIdentities(t *testing.T) {
+	for _, entry := range decideIdentitiesEntries {
+		t.Run(entry.name, func(t *testing.T) {
+			assert.Equal(t, entry.expected, decideIdentities(entry.users, entry.groups, entry.wanted))
+		})
+	}
+}
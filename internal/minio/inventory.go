@@ -0,0 +1,151 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/minio/madmin-go/v3"
+)
+
+const errNoSuchUser = "XMinioAdminNoSuchUser"
+
+// Inventory is the content of MinIO the orphan collection checks against the resources.
+type Inventory struct {
+	// Buckets maps every bucket to the owner recorded by its claim, empty when unclaimed
+	Buckets map[string]string
+	// Policies maps every canned policy to whether it carries ManagedPolicyID
+	Policies map[string]bool
+	// Users maps every user to the policies attached to it
+	Users map[string][]string
+	// Groups lists the groups with their members and the policies attached to them
+	Groups []Group
+	// LDAPBound lists the canned policies attached to LDAP users or groups
+	LDAPBound map[string]bool
+}
+
+// Inventory lists the buckets with their claim, the canned policies, the users, the groups
+// and the LDAP attachments.
+func (c *client) Inventory(ctx context.Context) (Inventory, error) {
+	inventory := Inventory{
+		Buckets: map[string]string{}, Policies: map[string]bool{},
+		Users: map[string][]string{}, LDAPBound: map[string]bool{},
+	}
+	buckets, err := c.ListBuckets(ctx)
+	if err != nil {
+		return inventory, err
+	}
+	for _, bucket := range buckets {
+		tags, err := c.bucketTags(ctx, bucket.Name)
+		if err != nil {
+			return inventory, err
+		}
+		inventory.Buckets[bucket.Name] = tags.ToMap()[ClaimTag]
+	}
+	policies, err := c.ListCannedPolicies(ctx)
+	if err != nil {
+		return inventory, err
+	}
+	for name, document := range policies {
+		inventory.Policies[name] = isManagedPolicy(document)
+	}
+	users, err := c.ListUsers(ctx)
+	if err != nil {
+		return inventory, err
+	}
+	for name, info := range users {
+		inventory.Users[name] = splitPolicies(info.PolicyName)
+	}
+	groups, err := c.ListGroups(ctx)
+	if err != nil {
+		return inventory, err
+	}
+	for _, name := range groups {
+		description, err := c.GetGroupDescription(ctx, name)
+		if err != nil {
+			return inventory, err
+		}
+		inventory.Groups = append(inventory.Groups, Group{
+			Name: name, Members: description.Members, Policies: splitPolicies(description.Policy),
+		})
+	}
+	ldap, err := c.GetLDAPPolicyEntities(ctx, madmin.PolicyEntitiesQuery{})
+	if madmin.ToErrorResponse(err).Code == errLDAPNotEnabled {
+		return inventory, nil
+	} else if err != nil {
+		return inventory, err
+	}
+	for _, mapping := range ldap.PolicyMappings {
+		if len(mapping.Users) > 0 || len(mapping.Groups) > 0 {
+			inventory.LDAPBound[mapping.Policy] = true
+		}
+	}
+	return inventory, nil
+}
+
+// splitPolicies parses the comma separated list of policies attached to a user.
+func splitPolicies(policies string) []string {
+	if policies == "" {
+		return nil
+	}
+	return strings.Split(policies, ",")
+}
+
+// UserDelete removes the user and its service accounts, a missing user is not an error.
+func (c *client) UserDelete(ctx context.Context, name string) error {
+	err := c.RemoveUser(ctx, name)
+	if madmin.ToErrorResponse(err).Code == errNoSuchUser {
+		return nil
+	}
+	return err
+}
+
+// CannedPolicyDelete detaches the canned policy from its users, groups and LDAP identities
+// before removing it. Unlike PolicyDelete the users are kept, and a canned policy which was not
+// created by the controller is left untouched.
+func (c *client) CannedPolicyDelete(ctx context.Context, name string) error {
+	current, err := c.cannedPolicy(ctx, name)
+	if err != nil || !isManagedPolicy(current) {
+		return err
+	}
+	result, err := c.GetPolicyEntities(ctx, madmin.PolicyEntitiesQuery{Policy: []string{name}})
+	if err != nil {
+		return err
+	}
+	errs := []error{}
+	for _, mapping := range result.PolicyMappings {
+		for _, user := range mapping.Users {
+			_, err := c.DetachPolicy(ctx, madmin.PolicyAssociationReq{Policies: []string{name}, User: user})
+			errs = append(errs, err)
+		}
+		for _, group := range mapping.Groups {
+			_, err := c.DetachPolicy(ctx, madmin.PolicyAssociationReq{Policies: []string{name}, Group: group})
+			errs = append(errs, err)
+		}
+	}
+	if users, groups, err := c.ldapEntities(ctx, name); err == nil {
+		errs = append(errs, c.ldapDetach(ctx, name, users, groups))
+	} else if madmin.ToErrorResponse(err).Code != errLDAPNotEnabled {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	if err := c.RemoveCannedPolicy(ctx, name); madmin.ToErrorResponse(err).Code != errNoSuchPolicy {
+		return err
+	}
+	return nil
+}
+
+// IsGeneratedUser reports whether the name has the format of the users generated by GenerateUser.
+func IsGeneratedUser(name string) bool {
+	if len(name) != accessKeyMaxLen || !strings.HasPrefix(name, GeneratedUserPrefix) {
+		return false
+	}
+	for _, char := range name[len(GeneratedUserPrefix):] {
+		if !strings.ContainsRune(alphaNumericTable, char) {
+			return false
+		}
+	}
+	return true
+}
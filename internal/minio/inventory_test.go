@@ -0,0 +1,24 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsGeneratedUser(t *testing.T) {
+	user, err := GenerateUser()
+	require.NoError(t, err)
+	assert.True(t, IsGeneratedUser(string(user)))
+	assert.False(t, IsGeneratedUser("admin"))
+	key, err := GenerateAccessKey(0, nil)
+	require.NoError(t, err)
+	assert.False(t, IsGeneratedUser(string(key)), "access keys of other tools are not users of the controller")
+	assert.False(t, IsGeneratedUser("abcdefghijklmnopqrst"))
+}
+
+func Test_splitPolicies(t *testing.T) {
+	assert.Nil(t, splitPolicies(""))
+	assert.Equal(t, []string{"readwrite", "default.bucket.reader"}, splitPolicies("readwrite,default.bucket.reader"))
+}
@@ -0,0 +1,138 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+const errNoSuchLifecycle = "NoSuchLifecycleConfiguration"
+
+var ErrInvalidLifecycleRule = errors.New("invalid lifecycle rule")
+
+type BucketLifecycle = v1alpha1.BucketLifecycle
+
+// BucketLifecycleReconcile converges the lifecycle configuration of the bucket,
+// the configuration is only pushed when it drifted from the wanted one.
+// A nil lifecycle leaves the bucket untouched.
+func (c *client) BucketLifecycleReconcile(ctx context.Context, name string, wanted *BucketLifecycle) (bool, error) {
+	if wanted == nil {
+		return false, nil
+	}
+	current, err := c.GetBucketLifecycle(ctx, name)
+	if minio.ToErrorResponse(err).Code == errNoSuchLifecycle {
+		current, err = lifecycle.NewConfiguration(), nil
+	}
+	if err != nil {
+		return false, err
+	}
+	expected, err := decideLifecycle(current, wanted)
+	if err != nil {
+		return false, err
+	}
+	if expected == nil {
+		return false, nil
+	}
+	return true, c.SetBucketLifecycle(ctx, name, expected)
+}
+
+func decideLifecycle(current *lifecycle.Configuration, wanted *BucketLifecycle) (*lifecycle.Configuration, error) {
+	expected, err := NewLifecycle(wanted.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if len(current.Rules) != len(expected.Rules) {
+		return expected, nil
+	}
+	currentJSON, err := marshalRules(current.Rules)
+	if err != nil {
+		return nil, err
+	}
+	expectedJSON, err := marshalRules(expected.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(currentJSON, expectedJSON) {
+		return nil, nil
+	}
+	return expected, nil
+}
+
+// marshalRules renders the rules in a canonical form, sorted by ID and with
+// their filter normalized, so configurations can be compared byte for byte.
+func marshalRules(rules []lifecycle.Rule) ([]byte, error) {
+	normalized := make([]lifecycle.Rule, len(rules))
+	for i, rule := range rules {
+		prefix, tags := rule.Prefix, map[string]string{}
+		for _, p := range []string{rule.RuleFilter.Prefix, rule.RuleFilter.And.Prefix} {
+			if p != "" {
+				prefix = p
+			}
+		}
+		for _, tag := range rule.RuleFilter.And.Tags {
+			tags[tag.Key] = tag.Value
+		}
+		if tag := rule.RuleFilter.Tag; !tag.IsEmpty() {
+			tags[tag.Key] = tag.Value
+		}
+		rule.Prefix, rule.RuleFilter = "", newFilter(prefix, tags)
+		normalized[i] = rule
+	}
+	slices.SortFunc(normalized, func(a, b lifecycle.Rule) int { return strings.Compare(a.ID, b.ID) })
+	return json.Marshal(normalized)
+}
+
+// NewLifecycle translates the rules of the custom resource into a MinIO lifecycle configuration.
+func NewLifecycle(rules []v1alpha1.LifecycleRule) (*lifecycle.Configuration, error) {
+	config := lifecycle.NewConfiguration()
+	for _, r := range rules {
+		if r.ID == "" || (r.ExpirationDays == 0 && r.NoncurrentVersionExpirationDays == 0 &&
+			r.AbortIncompleteMultipartUploadDays == 0) {
+			return nil, ErrInvalidLifecycleRule
+		}
+		rule := lifecycle.Rule{
+			ID:         r.ID,
+			Status:     "Enabled",
+			RuleFilter: newFilter(r.Prefix, r.Tags),
+		}
+		if r.Disabled {
+			rule.Status = "Disabled"
+		}
+		rule.Expiration.Days = lifecycle.ExpirationDays(r.ExpirationDays)
+		rule.NoncurrentVersionExpiration.NoncurrentDays = lifecycle.ExpirationDays(r.NoncurrentVersionExpirationDays)
+		rule.NoncurrentVersionExpiration.NewerNoncurrentVersions = int(r.NewerNoncurrentVersions)
+		rule.AbortIncompleteMultipartUpload.DaysAfterInitiation =
+			lifecycle.ExpirationDays(r.AbortIncompleteMultipartUploadDays)
+		config.Rules = append(config.Rules, rule)
+	}
+	return config, nil
+}
+
+// newFilter builds the filter the way S3 expects it: a single condition is set
+// directly on the filter, several ones are combined with an And.
+func newFilter(prefix string, tags map[string]string) lifecycle.Filter {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	switch {
+	case len(tags) == 0:
+		return lifecycle.Filter{Prefix: prefix}
+	case len(tags) == 1 && prefix == "":
+		return lifecycle.Filter{Tag: lifecycle.Tag{Key: keys[0], Value: tags[keys[0]]}}
+	}
+	and := lifecycle.And{Prefix: prefix, Tags: make([]lifecycle.Tag, len(keys))}
+	for i, key := range keys {
+		and.Tags[i] = lifecycle.Tag{Key: key, Value: tags[key]}
+	}
+	return lifecycle.Filter{And: and}
+}
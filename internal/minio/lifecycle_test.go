@@ -0,0 +1,63 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var lifecycleRules = []v1alpha1.LifecycleRule{
+	{ID: "logs", Prefix: "logs/", ExpirationDays: 30},
+	{ID: "tmp", Tags: map[string]string{"tmp": "true", "team": "ci"}, AbortIncompleteMultipartUploadDays: 1},
+	{ID: "versions", NoncurrentVersionExpirationDays: 7, NewerNoncurrentVersions: 3, Disabled: true},
+}
+
+func TestNewLifecycle(t *testing.T) {
+	config, err := NewLifecycle(lifecycleRules)
+	require.NoError(t, err)
+	require.Len(t, config.Rules, 3)
+
+	assert.Equal(t, "Enabled", config.Rules[0].Status)
+	assert.Equal(t, lifecycle.Filter{Prefix: "logs/"}, config.Rules[0].RuleFilter)
+	assert.Equal(t, lifecycle.ExpirationDays(30), config.Rules[0].Expiration.Days)
+
+	assert.Equal(t, []lifecycle.Tag{{Key: "team", Value: "ci"}, {Key: "tmp", Value: "true"}},
+		config.Rules[1].RuleFilter.And.Tags)
+
+	assert.Equal(t, "Disabled", config.Rules[2].Status)
+	assert.Equal(t, 3, config.Rules[2].NoncurrentVersionExpiration.NewerNoncurrentVersions)
+
+	_, err = NewLifecycle([]v1alpha1.LifecycleRule{{ID: "noop", Prefix: "logs/"}})
+	assert.ErrorIs(t, err, ErrInvalidLifecycleRule)
+}
+
+func Test_decideLifecycle(t *testing.T) {
+	wanted := &BucketLifecycle{Rules: lifecycleRules}
+	expected, err := NewLifecycle(lifecycleRules)
+	require.NoError(t, err)
+
+	got, err := decideLifecycle(lifecycle.NewConfiguration(), wanted)
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+
+	// same rules in another order, with the prefix set the legacy way
+	current := &lifecycle.Configuration{Rules: []lifecycle.Rule{
+		expected.Rules[2], expected.Rules[1], expected.Rules[0],
+	}}
+	current.Rules[2].RuleFilter, current.Rules[2].Prefix = lifecycle.Filter{}, "logs/"
+	got, err = decideLifecycle(current, wanted)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	current.Rules[2].Expiration.Days = 60
+	got, err = decideLifecycle(current, wanted)
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+
+	got, err = decideLifecycle(current, &BucketLifecycle{})
+	require.NoError(t, err)
+	assert.True(t, got.Empty())
+}
@@ -0,0 +1,210 @@
+package minio
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "minio_controller"
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the calls to MinIO, by client method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+	requestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "request_errors_total",
+		Help:      "Failed calls to MinIO, by client method.",
+	}, []string{"method"})
+	driftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "drift_corrections_total",
+		Help:      "Changes applied to MinIO to converge it to the resources, by client method.",
+	}, []string{"method"})
+)
+
+// Collectors returns the metrics of the instrumented clients, they are registered
+// once on the metrics registry of the manager.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{requestDuration, requestErrors, driftCorrections}
+}
+
+// Instrument wraps the client to record the duration and the errors of its calls,
+// the reconcile methods reporting a change also count as a drift correction.
+func Instrument(c Client) Client { return instrumented{c} }
+
+type instrumented struct {
+	Client
+}
+
+func observe(method string, start time.Time, err error) {
+	requestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		requestErrors.WithLabelValues(method).Inc()
+	}
+}
+
+func observeChange(method string, start time.Time, changed bool, err error) {
+	observe(method, start, err)
+	if changed && err == nil {
+		driftCorrections.WithLabelValues(method).Inc()
+	}
+}
+
+func (i instrumented) BucketCreate(ctx context.Context, name string, objectLocking bool) (err error) {
+	defer func(start time.Time) { observe("BucketCreate", start, err) }(time.Now())
+	return i.Client.BucketCreate(ctx, name, objectLocking)
+}
+
+func (i instrumented) BucketDelete(ctx context.Context, name string) (err error) {
+	defer func(start time.Time) { observe("BucketDelete", start, err) }(time.Now())
+	return i.Client.BucketDelete(ctx, name)
+}
+
+func (i instrumented) BucketPurge(ctx context.Context, name string, limit int) (_ int, err error) {
+	defer func(start time.Time) { observe("BucketPurge", start, err) }(time.Now())
+	return i.Client.BucketPurge(ctx, name, limit)
+}
+
+func (i instrumented) BucketClaim(ctx context.Context, name, owner string, adopt bool) (_ bool, err error) {
+	defer func(start time.Time) { observe("BucketClaim", start, err) }(time.Now())
+	return i.Client.BucketClaim(ctx, name, owner, adopt)
+}
+
+func (i instrumented) BucketCheckClaim(ctx context.Context, name, owner string, adopt bool) (err error) {
+	defer func(start time.Time) { observe("BucketCheckClaim", start, err) }(time.Now())
+	return i.Client.BucketCheckClaim(ctx, name, owner, adopt)
+}
+
+func (i instrumented) BucketRelease(ctx context.Context, name, owner string) (err error) {
+	defer func(start time.Time) { observe("BucketRelease", start, err) }(time.Now())
+	return i.Client.BucketRelease(ctx, name, owner)
+}
+
+func (i instrumented) BucketExists(ctx context.Context, name string) (_ bool, err error) {
+	defer func(start time.Time) { observe("BucketExists", start, err) }(time.Now())
+	return i.Client.BucketExists(ctx, name)
+}
+
+func (i instrumented) BucketPolicyReconcile(ctx context.Context, name string, policy BucketPolicy) (changed bool, err error) {
+	defer func(start time.Time) { observeChange("BucketPolicyReconcile", start, changed, err) }(time.Now())
+	return i.Client.BucketPolicyReconcile(ctx, name, policy)
+}
+
+func (i instrumented) BucketVersioningReconcile(
+	ctx context.Context, name string, versioning *BucketVersioning,
+) (_ VersioningStatus, changed bool, err error) {
+	defer func(start time.Time) { observeChange("BucketVersioningReconcile", start, changed, err) }(time.Now())
+	return i.Client.BucketVersioningReconcile(ctx, name, versioning)
+}
+
+func (i instrumented) BucketLifecycleReconcile(
+	ctx context.Context, name string, lifecycle *BucketLifecycle,
+) (changed bool, err error) {
+	defer func(start time.Time) { observeChange("BucketLifecycleReconcile", start, changed, err) }(time.Now())
+	return i.Client.BucketLifecycleReconcile(ctx, name, lifecycle)
+}
+
+func (i instrumented) BucketQuotaReconcile(ctx context.Context, name string, size uint64) (changed bool, err error) {
+	defer func(start time.Time) { observeChange("BucketQuotaReconcile", start, changed, err) }(time.Now())
+	return i.Client.BucketQuotaReconcile(ctx, name, size)
+}
+
+func (i instrumented) BucketUsage(ctx context.Context, name string) (_ uint64, err error) {
+	defer func(start time.Time) { observe("BucketUsage", start, err) }(time.Now())
+	return i.Client.BucketUsage(ctx, name)
+}
+
+func (i instrumented) BucketsUsage(ctx context.Context) (_ map[string]UsageInfo, err error) {
+	defer func(start time.Time) { observe("BucketsUsage", start, err) }(time.Now())
+	return i.Client.BucketsUsage(ctx)
+}
+
+func (i instrumented) BucketObjectLockReconcile(
+	ctx context.Context, name string, lock *BucketObjectLock,
+) (changed bool, err error) {
+	defer func(start time.Time) { observeChange("BucketObjectLockReconcile", start, changed, err) }(time.Now())
+	return i.Client.BucketObjectLockReconcile(ctx, name, lock)
+}
+
+func (i instrumented) BucketEncryptionReconcile(
+	ctx context.Context, name string, encryption *BucketEncryption,
+) (changed bool, err error) {
+	defer func(start time.Time) { observeChange("BucketEncryptionReconcile", start, changed, err) }(time.Now())
+	return i.Client.BucketEncryptionReconcile(ctx, name, encryption)
+}
+
+func (i instrumented) PolicyReconcile(ctx context.Context, policy *Policy) (changes PolicyChanges, err error) {
+	defer func(start time.Time) {
+		observeChange("PolicyReconcile", start, changes != PolicyChanges{}, err)
+	}(time.Now())
+	return i.Client.PolicyReconcile(ctx, policy)
+}
+
+func (i instrumented) PolicyDelete(ctx context.Context, name, user string) (err error) {
+	defer func(start time.Time) { observe("PolicyDelete", start, err) }(time.Now())
+	return i.Client.PolicyDelete(ctx, name, user)
+}
+
+func (i instrumented) IdentityPolicyReconcile(
+	ctx context.Context, policy *Policy, identities Identities,
+) (changes PolicyChanges, err error) {
+	defer func(start time.Time) {
+		observeChange("IdentityPolicyReconcile", start, changes != PolicyChanges{}, err)
+	}(time.Now())
+	return i.Client.IdentityPolicyReconcile(ctx, policy, identities)
+}
+
+func (i instrumented) ServiceAccountReconcile(ctx context.Context, account ServiceAccount) (changed bool, err error) {
+	defer func(start time.Time) { observeChange("ServiceAccountReconcile", start, changed, err) }(time.Now())
+	return i.Client.ServiceAccountReconcile(ctx, account)
+}
+
+func (i instrumented) ServiceAccountDelete(ctx context.Context, accessKey, parent string) (err error) {
+	defer func(start time.Time) { observe("ServiceAccountDelete", start, err) }(time.Now())
+	return i.Client.ServiceAccountDelete(ctx, accessKey, parent)
+}
+
+func (i instrumented) UserDelete(ctx context.Context, name string) (err error) {
+	defer func(start time.Time) { observe("UserDelete", start, err) }(time.Now())
+	return i.Client.UserDelete(ctx, name)
+}
+
+func (i instrumented) CannedPolicyDelete(ctx context.Context, name string) (err error) {
+	defer func(start time.Time) { observe("CannedPolicyDelete", start, err) }(time.Now())
+	return i.Client.CannedPolicyDelete(ctx, name)
+}
+
+func (i instrumented) GroupReconcile(ctx context.Context, group Group) (changes GroupChanges, err error) {
+	defer func(start time.Time) {
+		observeChange("GroupReconcile", start, changes != GroupChanges{}, err)
+	}(time.Now())
+	return i.Client.GroupReconcile(ctx, group)
+}
+
+func (i instrumented) GroupDelete(ctx context.Context, name string) (err error) {
+	defer func(start time.Time) { observe("GroupDelete", start, err) }(time.Now())
+	return i.Client.GroupDelete(ctx, name)
+}
+
+func (i instrumented) AssumeRole(
+	ctx context.Context, user string, duration time.Duration,
+) (_ TemporaryCredentials, err error) {
+	defer func(start time.Time) { observe("AssumeRole", start, err) }(time.Now())
+	return i.Client.AssumeRole(ctx, user, duration)
+}
+
+func (i instrumented) Inventory(ctx context.Context) (_ Inventory, err error) {
+	defer func(start time.Time) { observe("Inventory", start, err) }(time.Now())
+	return i.Client.Inventory(ctx)
+}
+
+func (i instrumented) Health(ctx context.Context) (err error) {
+	defer func(start time.Time) { observe("Health", start, err) }(time.Now())
+	return i.Client.Health(ctx)
+}
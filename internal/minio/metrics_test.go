@@ -0,0 +1,44 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/IxDay/api/v1alpha1"
+)
+
+// driftingStub reports a change on every bucket policy reconciliation and fails health checks.
+type driftingStub struct {
+	stub
+}
+
+func (driftingStub) BucketPolicyReconcile(context.Context, string, BucketPolicy) (bool, error) {
+	return true, nil
+}
+
+func (driftingStub) Health(context.Context) error { return errors.New("unreachable") }
+
+func TestInstrument(t *testing.T) {
+	client := Instrument(driftingStub{})
+	ctx := context.Background()
+
+	drifts := testutil.ToFloat64(driftCorrections.WithLabelValues("BucketPolicyReconcile"))
+	failures := testutil.ToFloat64(requestErrors.WithLabelValues("Health"))
+
+	changed, err := client.BucketPolicyReconcile(ctx, "bucket", v1alpha1.PolicyPrivate)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Error(t, client.Health(ctx))
+	_, err = client.BucketQuotaReconcile(ctx, "bucket", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, drifts+1, testutil.ToFloat64(driftCorrections.WithLabelValues("BucketPolicyReconcile")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(driftCorrections.WithLabelValues("BucketQuotaReconcile")))
+	assert.Equal(t, failures+1, testutil.ToFloat64(requestErrors.WithLabelValues("Health")))
+	assert.GreaterOrEqual(t, testutil.CollectAndCount(requestDuration), 3)
+}
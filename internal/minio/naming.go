@@ -0,0 +1,64 @@
+package minio
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"text/template"
+
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+const (
+	// DefaultNamingTemplate reproduces the historical "<namespace>.<name>" scheme.
+	DefaultNamingTemplate = "{{ .Namespace }}.{{ .Name }}"
+
+	bucketNameMaxLen = 63
+	hashSuffixLen    = 8
+)
+
+// NamingStrategy computes the name of the MinIO buckets from the resources.
+// The zero value uses the DefaultNamingTemplate.
+type NamingStrategy struct {
+	template  *template.Template
+	clusterID string
+}
+
+// NamingData holds the placeholders available in a naming template.
+type NamingData struct {
+	Namespace, Name, ClusterID string
+}
+
+// NewNamingStrategy parses the given template, placeholders are the fields of NamingData.
+func NewNamingStrategy(text, clusterID string) (NamingStrategy, error) {
+	tmpl, err := template.New("bucket").Parse(text)
+	if err != nil {
+		return NamingStrategy{}, err
+	}
+	return NamingStrategy{template: tmpl, clusterID: clusterID}, nil
+}
+
+// BucketName renders the name of the bucket, names longer than what S3 allows
+// are truncated and suffixed with a hash of the full name to keep them unique.
+func (n NamingStrategy) BucketName(namespace, name string) (string, error) {
+	tmpl := n.template
+	if tmpl == nil {
+		tmpl = template.Must(template.New("bucket").Parse(DefaultNamingTemplate))
+	}
+	buffer := strings.Builder{}
+	data := NamingData{Namespace: namespace, Name: name, ClusterID: n.clusterID}
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+	bucket := truncateName(strings.ToLower(buffer.String()))
+	return bucket, s3utils.CheckValidBucketNameStrict(bucket)
+}
+
+func truncateName(name string) string {
+	if len(name) <= bucketNameMaxLen {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	prefix := strings.TrimRight(name[:bucketNameMaxLen-hashSuffixLen-1], ".-")
+	return prefix + "-" + hex.EncodeToString(sum[:])[:hashSuffixLen]
+}
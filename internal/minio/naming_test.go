@@ -0,0 +1,38 @@
+package minio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingStrategy(t *testing.T) {
+	name, err := NamingStrategy{}.BucketName("default", "logs")
+	require.NoError(t, err)
+	assert.Equal(t, "default.logs", name)
+
+	strategy, err := NewNamingStrategy("{{ .ClusterID }}-{{ .Namespace }}-{{ .Name }}", "eu1")
+	require.NoError(t, err)
+	name, err = strategy.BucketName("default", "logs")
+	require.NoError(t, err)
+	assert.Equal(t, "eu1-default-logs", name)
+
+	long := strings.Repeat("a", 60)
+	name, err = strategy.BucketName("default", long)
+	require.NoError(t, err)
+	assert.Len(t, name, bucketNameMaxLen)
+	assert.True(t, strings.HasPrefix(name, "eu1-default-aaa"))
+	other, err := strategy.BucketName("default", long+"b")
+	require.NoError(t, err)
+	assert.NotEqual(t, name, other, "truncated names must stay unique")
+
+	strategy, err = NewNamingStrategy("{{ .Namespace }}_{{ .Name }}", "")
+	require.NoError(t, err)
+	_, err = strategy.BucketName("default", "logs")
+	assert.Error(t, err)
+
+	_, err = NewNamingStrategy("{{ .Namespace ", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	errNoSuchObjectLock = "ObjectLockConfigurationNotFoundError"
+	objectLockEnabled   = "Enabled"
+)
+
+var ErrObjectLockNotEnabled = errors.New("object lock can only be enabled when the bucket is created")
+
+// ErrObjectLockUnmanaged is returned when object lock is enabled on a bucket whose spec omits it,
+// the default retention found in MinIO is kept.
+var ErrObjectLockUnmanaged = errors.New("object lock is enabled on the bucket but omitted from the spec")
+
+type BucketObjectLock = v1alpha1.BucketObjectLock
+
+// objectLockRetention mirrors the default retention as handled by the minio client,
+// mode, validity and unit are either all set or all nil.
+type objectLockRetention struct {
+	Mode     *minio.RetentionMode
+	Validity *uint
+	Unit     *minio.ValidityUnit
+}
+
+// BucketObjectLockReconcile converges the default retention of the bucket, a nil lock leaves
+// the bucket untouched and ErrObjectLockUnmanaged reports the retention kept when object lock is
+// enabled. Object lock can not be enabled on an existing bucket, ErrObjectLockNotEnabled is
+// returned when the bucket was created without it.
+func (c *client) BucketObjectLockReconcile(ctx context.Context, name string, lock *BucketObjectLock) (bool, error) {
+	enabled, mode, validity, unit, err := c.GetObjectLockConfig(ctx, name)
+	if minio.ToErrorResponse(err).Code == errNoSuchObjectLock {
+		enabled, err = "", nil
+	}
+	if err != nil {
+		return false, err
+	}
+	current := objectLockRetention{Mode: mode, Validity: validity, Unit: unit}
+	expected, err := decideObjectLock(enabled == objectLockEnabled, current, lock)
+	if errors.Is(err, ErrObjectLockUnmanaged) {
+		return false, fmt.Errorf("%w, %s is kept", err, describeRetention(current))
+	}
+	if err != nil || expected == nil {
+		return false, err
+	}
+	return true, c.SetObjectLockConfig(ctx, name, expected.Mode, expected.Validity, expected.Unit)
+}
+
+func decideObjectLock(
+	enabled bool, current objectLockRetention, wanted *BucketObjectLock,
+) (*objectLockRetention, error) {
+
+	switch {
+	case wanted == nil && enabled:
+		// object lock is not managed, the retention in place is only reported
+		return nil, ErrObjectLockUnmanaged
+	case wanted == nil:
+		return nil, nil
+	case !enabled:
+		return nil, ErrObjectLockNotEnabled
+	}
+
+	expected := objectLockRetention{}
+	if retention := wanted.DefaultRetention; retention != nil {
+		mode, validity, unit := minio.RetentionMode(retention.Mode), uint(retention.Days), minio.Days
+		if retention.Years != 0 {
+			validity, unit = uint(retention.Years), minio.Years
+		}
+		expected = objectLockRetention{Mode: &mode, Validity: &validity, Unit: &unit}
+	}
+	if expected.Mode == nil && current.Mode == nil {
+		return nil, nil
+	}
+	if expected.Mode != nil && current.Mode != nil && current.Validity != nil && current.Unit != nil &&
+		*expected.Mode == *current.Mode && *expected.Validity == *current.Validity &&
+		*expected.Unit == *current.Unit {
+		return nil, nil
+	}
+	return &expected, nil
+}
+
+// describeRetention tells the default retention in place for the status of the bucket.
+func describeRetention(retention objectLockRetention) string {
+	if retention.Mode == nil || retention.Validity == nil || retention.Unit == nil {
+		return "no default retention"
+	}
+	return fmt.Sprintf("the default retention %s for %d %s", *retention.Mode, *retention.Validity, *retention.Unit)
+}
@@ -0,0 +1,63 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetention(mode minio.RetentionMode, validity uint, unit minio.ValidityUnit) objectLockRetention {
+	return objectLockRetention{Mode: &mode, Validity: &validity, Unit: &unit}
+}
+
+var thirtyDaysCompliance = &BucketObjectLock{DefaultRetention: &v1alpha1.ObjectLockRetention{
+	Mode: v1alpha1.RetentionCompliance, Days: 30,
+}}
+
+var decideObjectLockEntries = []struct {
+	enabled     bool
+	current     objectLockRetention
+	wanted      *BucketObjectLock
+	expected    *objectLockRetention
+	expectedErr error
+}{
+	{false, objectLockRetention{}, nil, nil, nil},
+	{true, newRetention(minio.Compliance, 30, minio.Days), nil, nil, ErrObjectLockUnmanaged},
+	{false, objectLockRetention{}, &BucketObjectLock{}, nil, ErrObjectLockNotEnabled},
+	{true, objectLockRetention{}, &BucketObjectLock{}, nil, nil},
+	{true, newRetention(minio.Compliance, 30, minio.Days), thirtyDaysCompliance, nil, nil},
+	{
+		true, objectLockRetention{}, thirtyDaysCompliance,
+		ptr(newRetention(minio.Compliance, 30, minio.Days)), nil,
+	},
+	{
+		true, newRetention(minio.Governance, 30, minio.Days), thirtyDaysCompliance,
+		ptr(newRetention(minio.Compliance, 30, minio.Days)), nil,
+	},
+	{
+		true, newRetention(minio.Compliance, 30, minio.Days),
+		&BucketObjectLock{DefaultRetention: &v1alpha1.ObjectLockRetention{
+			Mode: v1alpha1.RetentionCompliance, Years: 1,
+		}},
+		ptr(newRetention(minio.Compliance, 1, minio.Years)), nil,
+	},
+	{true, newRetention(minio.Compliance, 30, minio.Days), &BucketObjectLock{}, &objectLockRetention{}, nil},
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func Test_decideObjectLock(t *testing.T) {
+	for _, entry := range decideObjectLockEntries {
+		got, err := decideObjectLock(entry.enabled, entry.current, entry.wanted)
+		assert.ErrorIs(t, err, entry.expectedErr)
+		assert.Equal(t, entry.expected, got)
+	}
+}
+
+func Test_describeRetention(t *testing.T) {
+	assert.Equal(t, "no default retention", describeRetention(objectLockRetention{}))
+	assert.Equal(t, "the default retention COMPLIANCE for 30 DAYS",
+		describeRetention(newRetention(minio.Compliance, 30, minio.Days)))
+}
@@ -0,0 +1,125 @@
+package minio
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+var ErrInvalidCA = errors.New("no certificate found in CA bundle")
+var ErrInvalidCertificate = errors.New("invalid client certificate")
+
+// ClientOptions describes how to reach a MinIO server, TLS is only used when Secure is set.
+type ClientOptions struct {
+	Endpoint, User, Password string
+	// Region in which the buckets are created, defaults to us-east-1
+	Region string
+	Secure bool
+	// CA is a PEM bundle trusted in place of the system roots
+	CA []byte
+	// Certificate and Key are the PEM encoded client certificate presented for mutual TLS
+	Certificate, Key   []byte
+	InsecureSkipVerify bool
+	// secureSet and insecureSkipVerifySet record the flags given explicitly by the connection secret
+	secureSet, insecureSkipVerifySet bool
+}
+
+// WithTLS completes the TLS settings left unset with the given defaults, the flags given
+// explicitly by the connection secret, such as an http:// endpoint, take precedence.
+func (o ClientOptions) WithTLS(defaults ClientOptions) ClientOptions {
+	if !o.secureSet {
+		o.Secure = defaults.Secure
+	}
+	if !o.insecureSkipVerifySet {
+		o.InsecureSkipVerify = defaults.InsecureSkipVerify
+	}
+	if o.CA == nil {
+		o.CA = defaults.CA
+	}
+	if o.Certificate == nil && o.Key == nil {
+		o.Certificate, o.Key = defaults.Certificate, defaults.Key
+	}
+	return o
+}
+
+// NewClientWithOptions builds the S3 and admin clients sharing the same transport.
+func NewClientWithOptions(opts ClientOptions) (Client, error) {
+	region := opts.Region
+	if region == "" {
+		region = defaultLocation
+	}
+	transport, err := newTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	creds := credentials.NewStaticV4(opts.User, opts.Password, "")
+	minioClient, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds: creds, Secure: opts.Secure, Region: region, Transport: transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	minioAdminClient, err := madmin.NewWithOptions(opts.Endpoint, &madmin.Options{
+		Creds: creds, Secure: opts.Secure, Transport: transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &client{
+		Client: minioClient, AdminClient: minioAdminClient, region: region, transport: transport,
+		usage: &usageCache{},
+	}, nil
+}
+
+// newTransport returns nil, the default transport of the clients, unless the
+// certificate verification is customized or a client certificate is presented.
+func newTransport(opts ClientOptions) (http.RoundTripper, error) {
+	if !opts.Secure || (opts.CA == nil && opts.Certificate == nil && !opts.InsecureSkipVerify) {
+		return nil, nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: opts.InsecureSkipVerify}
+	if opts.CA != nil {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(opts.CA) {
+			return nil, ErrInvalidCA
+		}
+	}
+	if opts.Certificate != nil || opts.Key != nil {
+		certificate, err := tls.X509KeyPair(opts.Certificate, opts.Key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	transport, err := minio.DefaultTransport(true)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = config
+	return transport, nil
+}
+
+// Endpoint locates the server of a client, as the applications reach it.
+type Endpoint struct {
+	URL, Host, Region string
+	Secure            bool
+}
+
+// Endpoint returns the location of the server the client talks to.
+func (c *client) Endpoint() Endpoint {
+	endpoint := c.EndpointURL()
+	return Endpoint{URL: endpoint.String(), Host: endpoint.Host, Region: c.region, Secure: endpoint.Scheme == "https"}
+}
+
+// Health checks the server is reachable and the credentials are accepted.
+func (c *client) Health(ctx context.Context) error {
+	_, err := c.ServerInfo(ctx)
+	return err
+}
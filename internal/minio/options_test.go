@@ -0,0 +1,148 @@
+package minio
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// selfSignedCertificate returns a PEM encoded certificate and its key.
+func selfSignedCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func Test_newTransport_clientCertificate(t *testing.T) {
+	certificate, key := selfSignedCertificate(t)
+
+	transport, err := newTransport(ClientOptions{Secure: true, Certificate: certificate, Key: key, CA: certificate})
+	require.NoError(t, err)
+	require.IsType(t, &http.Transport{}, transport)
+	config := transport.(*http.Transport).TLSClientConfig
+	assert.Len(t, config.Certificates, 1)
+	assert.NotNil(t, config.RootCAs)
+	assert.False(t, config.InsecureSkipVerify)
+
+	_, err = newTransport(ClientOptions{Secure: true, Certificate: certificate})
+	assert.ErrorIs(t, err, ErrInvalidCertificate)
+}
+
+var optionsFromSecretEntries = []struct {
+	name     string
+	data     map[string]string
+	expected ClientOptions
+	err      error
+}{
+	{
+		name:     "plain endpoint",
+		data:     map[string]string{"endpoint": "minio:9000", "user": "admin", "password": "password"},
+		expected: ClientOptions{Endpoint: "minio:9000", User: "admin", Password: "password"},
+	},
+	{
+		name:     "http scheme",
+		data:     map[string]string{"endpoint": "http://minio:9000/", "user": "admin", "password": "password"},
+		expected: ClientOptions{Endpoint: "minio:9000", User: "admin", Password: "password", secureSet: true},
+	},
+	{
+		name: "https with verification settings",
+		data: map[string]string{
+			"endpoint": "https://minio:9000", "user": "admin", "password": "password",
+			"ca.crt": "ca", "tls.crt": "cert", "tls.key": "key", "insecureSkipVerify": "true",
+		},
+		expected: ClientOptions{
+			Endpoint: "minio:9000", User: "admin", Password: "password", Secure: true,
+			CA: []byte("ca"), Certificate: []byte("cert"), Key: []byte("key"), InsecureSkipVerify: true,
+			secureSet: true, insecureSkipVerifySet: true,
+		},
+	},
+	{
+		name: "unsupported scheme",
+		data: map[string]string{"endpoint": "ftp://minio:9000", "user": "admin", "password": "password"},
+		err:  ErrInvalidSecret,
+	},
+	{
+		name: "missing password",
+		data: map[string]string{"endpoint": "minio:9000", "user": "admin"},
+		err:  ErrInvalidSecret,
+	},
+	{
+		name: "certificate without key",
+		data: map[string]string{"endpoint": "minio:9000", "user": "admin", "password": "password", "tls.crt": "cert"},
+		err:  ErrInvalidSecret,
+	},
+	{
+		name: "invalid insecureSkipVerify",
+		data: map[string]string{
+			"endpoint": "minio:9000", "user": "admin", "password": "password", "insecureSkipVerify": "sure",
+		},
+		err: ErrInvalidSecret,
+	},
+}
+
+func TestOptionsFromSecret(t *testing.T) {
+	for _, entry := range optionsFromSecretEntries {
+		t.Run(entry.name, func(t *testing.T) {
+			secret := &corev1.Secret{Data: map[string][]byte{}}
+			for key, value := range entry.data {
+				secret.Data[key] = []byte(value)
+			}
+			actual, err := OptionsFromSecret(secret)
+			if entry.err != nil {
+				assert.ErrorIs(t, err, entry.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, entry.expected, actual)
+		})
+	}
+}
+
+func TestClientOptions_WithTLS(t *testing.T) {
+	defaults := ClientOptions{
+		Secure: true, CA: []byte("ca"), Certificate: []byte("cert"), Key: []byte("key"),
+	}
+
+	actual := ClientOptions{Endpoint: "minio:9000"}.WithTLS(defaults)
+	assert.Equal(t, ClientOptions{
+		Endpoint: "minio:9000", Secure: true, CA: []byte("ca"), Certificate: []byte("cert"), Key: []byte("key"),
+	}, actual)
+
+	actual = ClientOptions{CA: []byte("other"), Certificate: []byte("mine"), Key: []byte("mine")}.WithTLS(defaults)
+	assert.Equal(t, []byte("other"), actual.CA)
+	assert.Equal(t, []byte("mine"), actual.Certificate)
+	assert.Equal(t, []byte("mine"), actual.Key)
+
+	// the flags set by the secret take precedence, even to disable TLS or the verification
+	defaults.InsecureSkipVerify = true
+	secret := &corev1.Secret{Data: map[string][]byte{
+		"endpoint": []byte("http://minio:9000"), "user": []byte("admin"), "password": []byte("password"),
+		"insecureSkipVerify": []byte("false"),
+	}}
+	options, err := OptionsFromSecret(secret)
+	require.NoError(t, err)
+	actual = options.WithTLS(defaults)
+	assert.False(t, actual.Secure)
+	assert.False(t, actual.InsecureSkipVerify)
+}
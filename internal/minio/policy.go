@@ -2,25 +2,40 @@ package minio
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/IxDay/api/v1alpha1"
 	"github.com/minio/pkg/v3/policy"
 )
 
+// ManagedPolicyID is the ID recorded in the canned policies created by the controller,
+// the other canned policies are never taken over.
+const ManagedPolicyID = "minio.ixday.github.io/managed"
+
 var (
-	ErrInvalidAction   = errors.New("invalid action")
-	ErrInvalidSubPath  = errors.New("invalid subPath")
-	ErrInvalidUser     = errors.New("invalid user")
-	ErrInvalidPassword = errors.New("invalid password")
-	empty              = struct{}{}
+	// ErrPolicyNotManaged is returned when a canned policy of the same name was created outside of the controller.
+	ErrPolicyNotManaged = errors.New("canned policy already exists and is not managed by the controller")
+	ErrInvalidAction    = errors.New("invalid action")
+	ErrInvalidSubPath   = errors.New("invalid subPath")
+	ErrInvalidUser      = errors.New("invalid user")
+	ErrInvalidPassword  = errors.New("invalid password")
+	ErrOutOfBucket      = errors.New("statement reaches outside of the bucket")
+	// ErrDocumentID is returned for a raw document carrying an ID, it is replaced by ManagedPolicyID.
+	ErrDocumentID = errors.New("document ID is reserved to the controller")
+	empty         = struct{}{}
 )
 
 type Policy struct {
+	// User is attached to the canned policy, an empty Password leaves the one of an existing
+	// user untouched and a random one is set on creation
 	User struct {
 		Name, Password string
 	}
 	Name, Bucket string
 	*policy.Policy
+	// Document is the raw IAM policy, when set it is sent to MinIO instead of Policy
+	Document []byte
 }
 
 func (p *Policy) SetUser(user, password []byte) error {
@@ -38,6 +53,59 @@ func (p *Policy) SetPolicy(statements []v1alpha1.Statement) (err error) {
 	return
 }
 
+// SetDocument uses a raw IAM policy document, the document is forwarded unchanged.
+// Unless unrestricted, the statements granting access may only target the bucket.
+func (p *Policy) SetDocument(document string, unrestricted bool) error {
+	parsed, err := ParseDocument(document)
+	if err != nil {
+		return err
+	}
+	if !unrestricted {
+		if err := checkScope(p.Bucket, parsed); err != nil {
+			return err
+		}
+	}
+	p.Policy, p.Document = parsed, []byte(document)
+	return nil
+}
+
+// ParseDocument parses and validates a raw IAM policy document, its ID must be empty or ManagedPolicyID.
+func ParseDocument(document string) (*policy.Policy, error) {
+	parsed, err := policy.ParseConfig(strings.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.ID != "" && parsed.ID != ManagedPolicyID {
+		return nil, fmt.Errorf("%w: %s", ErrDocumentID, parsed.ID)
+	}
+	return parsed, nil
+}
+
+// checkScope ensures the allowing statements only grant S3 actions on the bucket,
+// denying statements can not widen the access and are left alone.
+func checkScope(bucket string, document *policy.Policy) error {
+	for _, statement := range document.Statements {
+		if statement.Effect != policy.Allow {
+			continue
+		}
+		if len(statement.NotResources) > 0 {
+			return fmt.Errorf("%w: NotResource %s", ErrOutOfBucket, statement.NotResources)
+		}
+		for action := range statement.Actions {
+			if !strings.HasPrefix(string(action), "s3:") {
+				return fmt.Errorf("%w: action %s", ErrOutOfBucket, action)
+			}
+		}
+		for resource := range statement.Resources {
+			if resource.Type != policy.ResourceARNS3 ||
+				(resource.Pattern != bucket && !strings.HasPrefix(resource.Pattern, bucket+"/")) {
+				return fmt.Errorf("%w: resource %s", ErrOutOfBucket, resource)
+			}
+		}
+	}
+	return nil
+}
+
 func NewDefaultPolicy(bucketName string) *Policy {
 	return &Policy{
 		Name: bucketName, Bucket: bucketName,
@@ -159,34 +227,75 @@ func PolicyUpload(bucketName string) *policy.BucketPolicy {
 	}
 }
 
+// StatementError locates the element of a statement which failed validation,
+// Field is the dotted path of the field in the custom resource and Index the
+// position in it, Index is negative when the field is not a list.
+type StatementError struct {
+	Field string
+	Index int
+	Value string
+	Err   error
+}
+
+func (e *StatementError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%s %q: %s", e.Field, e.Value, e.Err)
+	}
+	return fmt.Sprintf("%s[%d] %q: %s", e.Field, e.Index, e.Value, e.Err)
+}
+
+func (e *StatementError) Unwrap() error { return e.Err }
+
 func NewPolicy(bucketName string, statements []v1alpha1.Statement) (*policy.Policy, error) {
 	p := policy.Policy{Version: policy.DefaultVersion, Statements: make([]policy.Statement, len(statements))}
 	for i, statement := range statements {
-		resources := make(policy.ResourceSet, len(statement.SubPaths))
-		if len(resources) == 0 {
-			resources = policy.ResourceSet{policy.NewResource(bucketName): {}}
+		s, err := NewStatement(bucketName, statement)
+		if err != nil {
+			return nil, err
 		}
-		for _, path := range statement.SubPaths {
-			r := policy.NewResource(bucketName + "/" + path)
-			if !r.IsValid() {
-				return nil, ErrInvalidSubPath
-			}
-			resources[r] = empty
+		p.Statements[i] = s
+	}
+	return &p, nil
+}
+
+// NewStatement translates a statement of the custom resource, failures are reported
+// as a *StatementError wrapping ErrInvalidSubPath, ErrInvalidAction or ErrInvalidCondition.
+// Statements with conditions are checked as a whole, MinIO restricts the keys per action.
+func NewStatement(bucketName string, statement v1alpha1.Statement) (policy.Statement, error) {
+	resources := make(policy.ResourceSet, len(statement.SubPaths))
+	if len(resources) == 0 {
+		resources = policy.ResourceSet{policy.NewResource(bucketName): {}}
+	}
+	for i, path := range statement.SubPaths {
+		r := policy.NewResource(bucketName + "/" + path)
+		if path == "" || strings.HasPrefix(path, "/") || !r.IsValid() {
+			return policy.Statement{}, &StatementError{Field: "subPaths", Index: i, Value: path, Err: ErrInvalidSubPath}
 		}
-		actions := make(policy.ActionSet, len(statement.SubPaths))
-		for _, action := range statement.Actions {
-			a := policy.Action(action)
-			if !a.IsValid() {
-				return nil, ErrInvalidAction
-			}
-			actions[a] = empty
+		resources[r] = empty
+	}
+	actions := make(policy.ActionSet, len(statement.Actions))
+	for i, action := range statement.Actions {
+		a := policy.Action(action)
+		if !a.IsValid() {
+			return policy.Statement{}, &StatementError{Field: "actions", Index: i, Value: action, Err: ErrInvalidAction}
 		}
-
-		p.Statements[i] = policy.Statement{
-			Effect:    policy.Effect(statement.Effect),
-			Resources: resources,
-			Actions:   actions,
+		actions[a] = empty
+	}
+	conditions, err := NewConditions(statement.Conditions)
+	if err != nil {
+		return policy.Statement{}, err
+	}
+	s := policy.Statement{
+		Effect:     policy.Effect(statement.Effect),
+		Resources:  resources,
+		Actions:    actions,
+		Conditions: conditions,
+	}
+	if len(conditions) > 0 {
+		if err := s.Validate(); err != nil {
+			return policy.Statement{}, &StatementError{Field: "conditions", Index: -1,
+				Value: conditions.String(), Err: fmt.Errorf("%w: %s", ErrInvalidCondition, err)}
 		}
 	}
-	return &p, nil
+	return s, nil
 }
@@ -1,10 +1,13 @@
 package minio
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/IxDay/api/v1alpha1"
 	"github.com/minio/pkg/v3/policy"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var defaultPolicy = `{
@@ -45,3 +48,69 @@ func TestPublicPolicy(t *testing.T) {
 	actual := transformPolicy(PolicyPublic("foo"))
 	assert.Equal(t, expected, actual)
 }
+
+func TestNewStatement(t *testing.T) {
+	statement := v1alpha1.Statement{
+		Effect:   "Allow",
+		SubPaths: []string{"logs/*"},
+		Actions:  []string{"s3:GetObject"},
+	}
+	actual, err := NewStatement("foo", statement)
+	require.NoError(t, err)
+	assert.Contains(t, actual.Resources, policy.NewResource("foo/logs/*"))
+
+	statement.Actions = append(statement.Actions, "s3:Nope")
+	_, err = NewStatement("foo", statement)
+	assert.ErrorIs(t, err, ErrInvalidAction)
+	assert.Equal(t, &StatementError{Field: "actions", Index: 1, Value: "s3:Nope", Err: ErrInvalidAction}, err)
+
+	statement.SubPaths = []string{"logs/*", "/abs"}
+	_, err = NewStatement("foo", statement)
+	assert.Equal(t, &StatementError{Field: "subPaths", Index: 1, Value: "/abs", Err: ErrInvalidSubPath}, err)
+}
+
+var documentPolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": ["s3:GetObject"],
+      "Resource": ["arn:aws:s3:::foo/*"],
+      "Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}
+    },
+    {
+      "Effect": "Deny",
+      "NotAction": ["s3:GetObject"],
+      "Resource": ["arn:aws:s3:::*"]
+    }
+  ]
+}`
+
+func TestSetDocument(t *testing.T) {
+	p := &Policy{Name: "policy", Bucket: "foo"}
+	require.NoError(t, p.SetDocument(documentPolicy, false))
+	assert.Equal(t, []byte(documentPolicy), p.Document)
+	assert.Len(t, p.Statements, 2)
+
+	p.Bucket = "bar"
+	assert.ErrorIs(t, p.SetDocument(documentPolicy, false), ErrOutOfBucket)
+	assert.NoError(t, p.SetDocument(documentPolicy, true))
+
+	// foo-other is matched by a foo* pattern but is not the bucket
+	wildcard := strings.ReplaceAll(documentPolicy, "foo/*", "foo*")
+	p.Bucket = "foo"
+	assert.ErrorIs(t, p.SetDocument(wildcard, false), ErrOutOfBucket)
+
+	admin := strings.ReplaceAll(documentPolicy, `["s3:GetObject"],
+      "Resource"`, `["admin:*"],
+      "Resource"`)
+	assert.ErrorIs(t, p.SetDocument(admin, false), ErrOutOfBucket)
+
+	assert.Error(t, p.SetDocument(`{"Version": "2012-10-17", "Statement": [{"Effect": "Maybe"}]}`, true))
+
+	// the ID marks the canned policies managed by the controller
+	identified := strings.Replace(documentPolicy, "{", `{"ID": "reader",`, 1)
+	assert.ErrorIs(t, p.SetDocument(identified, false), ErrDocumentID)
+	managed := strings.Replace(documentPolicy, "{", `{"ID": "`+ManagedPolicyID+`",`, 1)
+	assert.NoError(t, p.SetDocument(managed, false))
+}
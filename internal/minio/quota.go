@@ -0,0 +1,102 @@
+package minio
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+)
+
+// usageCacheTTL bounds the age of the data usage shared by the buckets of a connection,
+// MinIO only refreshes it once per scanner cycle anyway.
+const usageCacheTTL = time.Minute
+
+// BucketQuotaReconcile converges the hard quota of the bucket to the given size
+// in bytes, a zero size removes the quota.
+func (c *client) BucketQuotaReconcile(ctx context.Context, name string, size uint64) (bool, error) {
+	current, err := c.GetBucketQuota(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	expected := decideQuota(current, size)
+	if expected == nil {
+		return false, nil
+	}
+	return true, c.SetBucketQuota(ctx, name, expected)
+}
+
+// BucketUsage returns the size in bytes of the bucket, as computed by the
+// last data usage scan of the MinIO cluster.
+func (c *client) BucketUsage(ctx context.Context, name string) (uint64, error) {
+	usage, err := c.BucketsUsage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return usage[name].Size, nil
+}
+
+func decideQuota(current madmin.BucketQuota, size uint64) *madmin.BucketQuota {
+	currentSize := current.Size
+	if currentSize == 0 {
+		// servers predating the Size field only report the deprecated Quota one
+		currentSize = current.Quota
+	}
+	if currentSize == size {
+		return nil
+	}
+	if size == 0 {
+		return &madmin.BucketQuota{}
+	}
+	return &madmin.BucketQuota{Size: size, Quota: size, Type: madmin.HardQuota}
+}
+
+// UsageInfo is the size and the number of objects of a bucket.
+type UsageInfo struct {
+	Size    uint64
+	Objects uint64
+}
+
+// BucketsUsage returns the usage of every bucket, as computed by the last data
+// usage scan of the MinIO cluster. The cluster wide report is fetched at most
+// once per usageCacheTTL and shared by all the buckets of the connection.
+func (c *client) BucketsUsage(ctx context.Context) (map[string]UsageInfo, error) {
+	return c.usage.get(ctx, time.Now(), c.fetchUsage)
+}
+
+func (c *client) fetchUsage(ctx context.Context) (map[string]UsageInfo, error) {
+	usage, err := c.DataUsageInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make(map[string]UsageInfo, len(usage.BucketsUsage))
+	for name, bucket := range usage.BucketsUsage {
+		buckets[name] = UsageInfo{Size: bucket.Size, Objects: bucket.ObjectsCount}
+	}
+	return buckets, nil
+}
+
+// usageCache holds the last data usage report of a connection.
+type usageCache struct {
+	mutex     sync.Mutex
+	fetchedAt time.Time
+	buckets   map[string]UsageInfo
+}
+
+// get returns the cached report, fetching a new one when it is older than usageCacheTTL.
+// The report is shared and must not be modified.
+func (u *usageCache) get(
+	ctx context.Context, now time.Time, fetch func(context.Context) (map[string]UsageInfo, error),
+) (map[string]UsageInfo, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.buckets != nil && now.Sub(u.fetchedAt) < usageCacheTTL {
+		return u.buckets, nil
+	}
+	buckets, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u.buckets, u.fetchedAt = buckets, now
+	return buckets, nil
+}
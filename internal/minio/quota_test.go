@@ -0,0 +1,54 @@
+package minio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var decideQuotaEntries = []struct {
+	current  madmin.BucketQuota
+	size     uint64
+	expected *madmin.BucketQuota
+}{
+	{madmin.BucketQuota{}, 0, nil},
+	{madmin.BucketQuota{}, 1024, &madmin.BucketQuota{Size: 1024, Quota: 1024, Type: madmin.HardQuota}},
+	{madmin.BucketQuota{Size: 1024, Type: madmin.HardQuota}, 1024, nil},
+	{madmin.BucketQuota{Quota: 1024, Type: madmin.HardQuota}, 1024, nil},
+	{madmin.BucketQuota{Size: 1024, Type: madmin.HardQuota}, 2048,
+		&madmin.BucketQuota{Size: 2048, Quota: 2048, Type: madmin.HardQuota}},
+	{madmin.BucketQuota{Size: 1024, Type: madmin.HardQuota}, 0, &madmin.BucketQuota{}},
+}
+
+func Test_decideQuota(t *testing.T) {
+	for _, entry := range decideQuotaEntries {
+		assert.Equal(t, entry.expected, decideQuota(entry.current, entry.size))
+	}
+}
+
+func Test_usageCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	fetches := 0
+	fetch := func(context.Context) (map[string]UsageInfo, error) {
+		fetches++
+		return map[string]UsageInfo{"bucket": {Size: uint64(fetches)}}, nil
+	}
+	cache := &usageCache{}
+
+	usage, err := cache.get(ctx, now, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), usage["bucket"].Size)
+	// the buckets of the connection share the report
+	usage, err = cache.get(ctx, now.Add(usageCacheTTL/2), fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), usage["bucket"].Size)
+
+	usage, err = cache.get(ctx, now.Add(usageCacheTTL), fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), usage["bucket"].Size)
+	assert.Equal(t, 2, fetches)
+}
@@ -0,0 +1,125 @@
+package minio
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/IxDay/api/v1alpha1"
+)
+
+var ErrConnectionNotReady = errors.New("connection is not ready")
+
+// Registry caches one client per connection resource, a client is only rebuilt
+// when the options of its connection change. Resources without connection
+// reference use the default client.
+type Registry struct {
+	mutex   sync.RWMutex
+	def     registryEntry
+	clients map[string]registryEntry
+	// build is swapped in tests to avoid dialing MinIO
+	build func(ClientOptions) (Client, error)
+}
+
+type registryEntry struct {
+	options ClientOptions
+	client  Client
+}
+
+// NewRegistry returns a registry falling back on the given client, which may be nil.
+// The clients built by the registry are instrumented.
+func NewRegistry(def Client) *Registry {
+	return &Registry{def: registryEntry{client: def}, clients: map[string]registryEntry{}, build: newInstrumentedClient}
+}
+
+func newInstrumentedClient(options ClientOptions) (Client, error) {
+	client, err := NewClientWithOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	return Instrument(client), nil
+}
+
+// SetDefault swaps the default client when its options change, it reports whether
+// a new client was built. Reconciliations in progress keep the client they resolved.
+func (r *Registry) SetDefault(options ClientOptions) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.def.client != nil && reflect.DeepEqual(r.def.options, options) {
+		return false, nil
+	}
+	client, err := r.build(options)
+	if err != nil {
+		return false, err
+	}
+	r.def = registryEntry{options: options, client: client}
+	return true, nil
+}
+
+// ConnectionKey identifies a connection in the registry, cluster wide connections ignore the namespace.
+func ConnectionKey(kind, namespace, name string) string {
+	if kind == v1alpha1.KindClusterMinioConnection {
+		namespace = ""
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
+// Set registers the client of a connection and returns it.
+func (r *Registry) Set(key string, options ClientOptions) (Client, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if entry, ok := r.clients[key]; ok && reflect.DeepEqual(entry.options, options) {
+		return entry.client, nil
+	}
+	client, err := r.build(options)
+	if err != nil {
+		delete(r.clients, key)
+		return nil, err
+	}
+	r.clients[key] = registryEntry{options: options, client: client}
+	return client, nil
+}
+
+// Delete forgets the client of a connection.
+func (r *Registry) Delete(key string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.clients, key)
+}
+
+// Connections returns the registered clients by connection key, the default client
+// is returned under the empty key.
+func (r *Registry) Connections() map[string]Client {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	clients := make(map[string]Client, len(r.clients)+1)
+	if r.def.client != nil {
+		clients[""] = r.def.client
+	}
+	for key, entry := range r.clients {
+		clients[key] = entry.client
+	}
+	return clients
+}
+
+// Client returns the client of the referenced connection, resolved in the given namespace.
+// ErrConnectionNotReady is returned until the connection has been reconciled.
+func (r *Registry) Client(ref *v1alpha1.ConnectionReference, namespace string) (Client, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if ref == nil {
+		if r.def.client == nil {
+			return nil, ErrConnectionNotReady
+		}
+		return r.def.client, nil
+	}
+	kind := ref.Kind
+	if kind == "" {
+		kind = v1alpha1.KindMinioConnection
+	}
+	entry, ok := r.clients[ConnectionKey(kind, namespace, ref.Name)]
+	if !ok {
+		return nil, ErrConnectionNotReady
+	}
+	return entry.client, nil
+}
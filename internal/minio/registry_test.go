@@ -0,0 +1,104 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	def, builds := NewStub(), 0
+	registry := NewRegistry(def)
+	registry.build = func(ClientOptions) (Client, error) {
+		builds++
+		return &client{}, nil
+	}
+
+	actual, err := registry.Client(nil, "default")
+	require.NoError(t, err)
+	assert.Equal(t, def, actual)
+
+	ref := &v1alpha1.ConnectionReference{Name: "remote"}
+	_, err = registry.Client(ref, "default")
+	assert.ErrorIs(t, err, ErrConnectionNotReady)
+
+	key := ConnectionKey(v1alpha1.KindMinioConnection, "default", "remote")
+	options := ClientOptions{Endpoint: "minio:9000", User: "admin", Password: "password", CA: []byte("ca")}
+	expected, err := registry.Set(key, options)
+	require.NoError(t, err)
+	actual, err = registry.Client(ref, "default")
+	require.NoError(t, err)
+	assert.Same(t, expected, actual)
+
+	// namespaced connections are not visible from other namespaces
+	_, err = registry.Client(ref, "other")
+	assert.ErrorIs(t, err, ErrConnectionNotReady)
+
+	// the client is only rebuilt when the options change
+	_, err = registry.Set(key, ClientOptions{Endpoint: "minio:9000", User: "admin", Password: "password", CA: []byte("ca")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, builds)
+	options.Password = "rotated"
+	_, err = registry.Set(key, options)
+	require.NoError(t, err)
+	assert.Equal(t, 2, builds)
+
+	cluster := &v1alpha1.ConnectionReference{Kind: v1alpha1.KindClusterMinioConnection, Name: "shared"}
+	_, err = registry.Set(ConnectionKey(v1alpha1.KindClusterMinioConnection, "", "shared"), options)
+	require.NoError(t, err)
+	_, err = registry.Client(cluster, "any")
+	assert.NoError(t, err)
+	assert.Len(t, registry.Connections(), 3)
+	assert.Equal(t, def, registry.Connections()[""])
+
+	registry.Delete(key)
+	_, err = registry.Client(ref, "default")
+	assert.ErrorIs(t, err, ErrConnectionNotReady)
+
+	_, err = NewRegistry(nil).Client(nil, "default")
+	assert.ErrorIs(t, err, ErrConnectionNotReady)
+}
+
+func Test_newTransport(t *testing.T) {
+	transport, err := newTransport(ClientOptions{CA: []byte("ignored without TLS")})
+	require.NoError(t, err)
+	assert.Nil(t, transport)
+
+	_, err = newTransport(ClientOptions{Secure: true, CA: []byte("not a certificate")})
+	assert.ErrorIs(t, err, ErrInvalidCA)
+
+	transport, err = newTransport(ClientOptions{Secure: true, InsecureSkipVerify: true})
+	require.NoError(t, err)
+	assert.NotNil(t, transport)
+}
+
+func TestRegistry_SetDefault(t *testing.T) {
+	builds := 0
+	registry := NewRegistry(nil)
+	registry.build = func(ClientOptions) (Client, error) {
+		builds++
+		return &client{}, nil
+	}
+
+	options := ClientOptions{Endpoint: "minio:9000", User: "admin", Password: "password"}
+	changed, err := registry.SetDefault(options)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	previous, err := registry.Client(nil, "default")
+	require.NoError(t, err)
+
+	changed, err = registry.SetDefault(options)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	options.Password = "rotated"
+	changed, err = registry.SetDefault(options)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	actual, err := registry.Client(nil, "default")
+	require.NoError(t, err)
+	assert.NotSame(t, previous, actual)
+	assert.Equal(t, 2, builds)
+}
@@ -0,0 +1,125 @@
+package minio
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/IxDay/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var ErrInvalidSecretTemplate = errors.New("invalid secret template")
+
+// reservedKeys hold the credentials managed by the controller, templates can not override them
+var reservedKeys = map[string]struct{}{
+	"user": empty, "password": empty, "previousUser": empty, "previousPassword": empty,
+	"accessKey": empty, "secretKey": empty, "sessionToken": empty, "expiration": empty,
+}
+
+// omittedWhenEmpty are the preset keys only rendered for temporary credentials
+var omittedWhenEmpty = map[string]struct{}{"AWS_SESSION_TOKEN": empty}
+
+// presets maps the keys rendered by each preset to their template
+var presets = map[v1alpha1.SecretPreset]map[string]string{
+	v1alpha1.PresetAWSEnv: {
+		"AWS_ACCESS_KEY_ID":     "{{ .User }}",
+		"AWS_SECRET_ACCESS_KEY": "{{ .Password }}",
+		"AWS_SESSION_TOKEN":     "{{ .SessionToken }}",
+		"AWS_REGION":            "{{ .Region }}",
+		"AWS_ENDPOINT_URL":      "{{ .Endpoint }}",
+		"BUCKET_NAME":           "{{ .Bucket }}",
+	},
+	v1alpha1.PresetRclone: {
+		"rclone.conf": `[minio]
+type = s3
+provider = Minio
+access_key_id = {{ .User }}
+secret_access_key = {{ .Password }}
+{{ with .SessionToken }}session_token = {{ . }}
+{{ end }}endpoint = {{ .Endpoint }}
+region = {{ .Region }}
+`,
+	},
+	v1alpha1.PresetS3cmd: {
+		".s3cfg": `[default]
+access_key = {{ .User }}
+secret_key = {{ .Password }}
+{{ with .SessionToken }}access_token = {{ . }}
+{{ end }}host_base = {{ .Host }}
+host_bucket = {{ .Host }}
+bucket_location = {{ .Region }}
+use_https = {{ if .Secure }}True{{ else }}False{{ end }}
+`,
+	},
+	v1alpha1.PresetS3URL: {
+		"S3_URL": "s3://{{ userinfo .User .Password }}@{{ .Host }}/{{ .Bucket }}",
+	},
+}
+
+var secretFuncs = template.FuncMap{
+	// userinfo escapes the credentials for the user information part of an URL
+	"userinfo": func(user, password string) string { return url.UserPassword(user, password).String() },
+}
+
+// SecretData holds the placeholders available in a secret template.
+type SecretData struct {
+	Endpoint, Host, Region, Bucket, User, Password string
+	// SessionToken is only set for temporary credentials
+	SessionToken string
+	Secure       bool
+}
+
+// SecretTemplate renders the keys of a Secret, keyed by their name.
+type SecretTemplate map[string]*template.Template
+
+// NewSecretTemplate parses the presets and the templates of the spec, a nil spec renders nothing.
+func NewSecretTemplate(spec *v1alpha1.SecretTemplate) (SecretTemplate, error) {
+	if spec == nil {
+		return SecretTemplate{}, nil
+	}
+	texts := map[string]string{}
+	for _, preset := range spec.Presets {
+		keys, ok := presets[preset]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown preset %q", ErrInvalidSecretTemplate, preset)
+		}
+		maps.Copy(texts, keys)
+	}
+	maps.Copy(texts, spec.Data)
+
+	templates := SecretTemplate{}
+	for key, text := range texts {
+		if _, ok := reservedKeys[key]; ok {
+			return nil, fmt.Errorf("%w: key %q is managed by the controller", ErrInvalidSecretTemplate, key)
+		}
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return nil, fmt.Errorf("%w: key %q: %s", ErrInvalidSecretTemplate, key, strings.Join(errs, ", "))
+		}
+		tmpl, err := template.New(key).Funcs(secretFuncs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSecretTemplate, err)
+		}
+		templates[key] = tmpl
+	}
+	return templates, nil
+}
+
+// Render executes the templates with the given data.
+func (t SecretTemplate) Render(data SecretData) (map[string][]byte, error) {
+	rendered := make(map[string][]byte, len(t))
+	for key, tmpl := range t {
+		buffer := strings.Builder{}
+		if err := tmpl.Execute(&buffer, data); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSecretTemplate, err)
+		}
+		if _, ok := omittedWhenEmpty[key]; ok && buffer.Len() == 0 {
+			continue
+		}
+		rendered[key] = []byte(buffer.String())
+	}
+	return rendered, nil
+}
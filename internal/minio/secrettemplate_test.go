@@ -0,0 +1,72 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/IxDay/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var secretData = SecretData{
+	Endpoint: "https://minio:9000", Host: "minio:9000", Secure: true, Region: "eu-west-1",
+	Bucket: "default.data", User: "user", Password: "pass/word+",
+}
+
+func TestSecretTemplate_Render(t *testing.T) {
+	templates, err := NewSecretTemplate(&v1alpha1.SecretTemplate{
+		Presets: []v1alpha1.SecretPreset{v1alpha1.PresetAWSEnv, v1alpha1.PresetS3URL, v1alpha1.PresetS3cmd},
+		Data:    map[string]string{"AWS_REGION": "us-east-1", "DSN": "{{ .Host }}/{{ .Bucket }}"},
+	})
+	require.NoError(t, err)
+
+	rendered, err := templates.Render(secretData)
+	require.NoError(t, err)
+	assert.Equal(t, "user", string(rendered["AWS_ACCESS_KEY_ID"]))
+	assert.Equal(t, "https://minio:9000", string(rendered["AWS_ENDPOINT_URL"]))
+	assert.Equal(t, "us-east-1", string(rendered["AWS_REGION"]), "data takes precedence over presets")
+	assert.Equal(t, "s3://user:pass%2Fword+@minio:9000/default.data", string(rendered["S3_URL"]))
+	assert.Contains(t, string(rendered[".s3cfg"]), "use_https = True\n")
+	assert.Equal(t, "minio:9000/default.data", string(rendered["DSN"]))
+	assert.NotContains(t, rendered, "AWS_SESSION_TOKEN", "static credentials have no session token")
+	assert.NotContains(t, string(rendered[".s3cfg"]), "access_token")
+}
+
+func TestSecretTemplate_RenderSessionToken(t *testing.T) {
+	templates, err := NewSecretTemplate(&v1alpha1.SecretTemplate{
+		Presets: []v1alpha1.SecretPreset{v1alpha1.PresetAWSEnv, v1alpha1.PresetRclone, v1alpha1.PresetS3cmd},
+	})
+	require.NoError(t, err)
+
+	data := secretData
+	data.SessionToken = "token"
+	rendered, err := templates.Render(data)
+	require.NoError(t, err)
+	assert.Equal(t, "token", string(rendered["AWS_SESSION_TOKEN"]))
+	assert.Contains(t, string(rendered["rclone.conf"]), "\nsession_token = token\n")
+	assert.Contains(t, string(rendered[".s3cfg"]), "\naccess_token = token\n")
+}
+
+func TestNewSecretTemplate(t *testing.T) {
+	templates, err := NewSecretTemplate(nil)
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+
+	for _, data := range []map[string]string{
+		{"user": "{{ .User }}"},
+		{"sessionToken": "{{ .SessionToken }}"},
+		{"not/a/key": "{{ .User }}"},
+		{"url": "{{ .Host "},
+	} {
+		_, err := NewSecretTemplate(&v1alpha1.SecretTemplate{Data: data})
+		assert.ErrorIs(t, err, ErrInvalidSecretTemplate, data)
+	}
+
+	_, err = NewSecretTemplate(&v1alpha1.SecretTemplate{Presets: []v1alpha1.SecretPreset{"boto"}})
+	assert.ErrorIs(t, err, ErrInvalidSecretTemplate)
+
+	templates, err = NewSecretTemplate(&v1alpha1.SecretTemplate{Data: map[string]string{"url": "{{ .Unknown }}"}})
+	require.NoError(t, err)
+	_, err = templates.Render(secretData)
+	assert.ErrorIs(t, err, ErrInvalidSecretTemplate)
+}
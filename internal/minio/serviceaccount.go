@@ -0,0 +1,112 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+)
+
+const (
+	errNoSuchServiceAccount = "XMinioAdminServiceAccountNotFound"
+
+	accountStatusOn  = "on"
+	accountStatusOff = "off"
+)
+
+// ErrServiceAccountNotOwned is returned for an access key held by a service account of another user,
+// it is never modified nor deleted.
+var ErrServiceAccountNotOwned = errors.New("service account belongs to another user")
+
+// noExpiration clears the expiration of an existing service account.
+var noExpiration = time.Unix(0, 0).UTC()
+
+// ServiceAccount is an access key inheriting the policy of its parent user.
+type ServiceAccount struct {
+	Parent, AccessKey, SecretKey string
+	// Name labels the access key in MinIO, it is left untouched when empty
+	Name string
+	// Expiration disables the access key once reached, it never expires when nil
+	Expiration *time.Time
+	// Disabled refuses the requests signed by the access key
+	Disabled bool
+}
+
+// ServiceAccountReconcile creates the service account when missing and converges the name, the status
+// and the expiration of an existing one. The secret key is only set on creation, an account belonging
+// to another parent is refused with ErrServiceAccountNotOwned.
+func (c *client) ServiceAccountReconcile(ctx context.Context, account ServiceAccount) (bool, error) {
+	info, err := c.InfoServiceAccount(ctx, account.AccessKey)
+	switch {
+	case err == nil && info.ParentUser == account.Parent:
+		update := decideServiceAccount(info, account)
+		if update == nil {
+			return false, nil
+		}
+		return true, c.UpdateServiceAccount(ctx, account.AccessKey, *update)
+	case err == nil:
+		return false, fmt.Errorf("%w: %s", ErrServiceAccountNotOwned, account.AccessKey)
+	case madmin.ToErrorResponse(err).Code != errNoSuchServiceAccount:
+		return false, err
+	}
+	_, err = c.AddServiceAccount(ctx, madmin.AddServiceAccountReq{
+		TargetUser: account.Parent, AccessKey: account.AccessKey,
+		SecretKey: account.SecretKey, Name: account.Name, Expiration: account.Expiration,
+	})
+	if err == nil && account.Disabled {
+		// accounts are always created enabled
+		err = c.UpdateServiceAccount(ctx, account.AccessKey, madmin.UpdateServiceAccountReq{NewStatus: accountStatusOff})
+	}
+	return err == nil, err
+}
+
+// decideServiceAccount returns the update converging the account, nil when it already matches.
+func decideServiceAccount(current madmin.InfoServiceAccountResp, wanted ServiceAccount) *madmin.UpdateServiceAccountReq {
+	update := madmin.UpdateServiceAccountReq{}
+	status := accountStatusOn
+	if wanted.Disabled {
+		status = accountStatusOff
+	}
+	if current.AccountStatus != status {
+		update.NewStatus = status
+	}
+	if wanted.Name != "" && current.Name != wanted.Name {
+		update.NewName = wanted.Name
+	}
+	expiration := noExpiration
+	if wanted.Expiration != nil {
+		expiration = wanted.Expiration.UTC().Truncate(time.Second)
+	}
+	currentExpiration := noExpiration
+	if current.Expiration != nil && !current.Expiration.IsZero() {
+		currentExpiration = current.Expiration.UTC().Truncate(time.Second)
+	}
+	if !currentExpiration.Equal(expiration) {
+		update.NewExpiration = &expiration
+	}
+	if update.NewStatus == "" && update.NewName == "" && update.NewExpiration == nil {
+		return nil
+	}
+	return &update
+}
+
+// ServiceAccountDelete removes the service account of parent, it succeeds if the account is already
+// gone. An account belonging to another parent is refused with ErrServiceAccountNotOwned.
+func (c *client) ServiceAccountDelete(ctx context.Context, accessKey, parent string) error {
+	info, err := c.InfoServiceAccount(ctx, accessKey)
+	switch {
+	case madmin.ToErrorResponse(err).Code == errNoSuchServiceAccount:
+		return nil
+	case err != nil:
+		return err
+	case info.ParentUser != parent:
+		return fmt.Errorf("%w: %s", ErrServiceAccountNotOwned, accessKey)
+	}
+	err = c.DeleteServiceAccount(ctx, accessKey)
+	if madmin.ToErrorResponse(err).Code == errNoSuchServiceAccount {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package minio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	saExpiration = time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	saLater      = saExpiration.Add(time.Hour)
+)
+
+var decideServiceAccountEntries = []struct {
+	name     string
+	current  madmin.InfoServiceAccountResp
+	wanted   ServiceAccount
+	expected *madmin.UpdateServiceAccountReq
+}{
+	{
+		name:    "in sync",
+		current: madmin.InfoServiceAccountResp{AccountStatus: "on", Name: "ci", Expiration: &noExpiration},
+		wanted:  ServiceAccount{Name: "ci"},
+	},
+	{
+		name:    "unnamed is left untouched",
+		current: madmin.InfoServiceAccountResp{AccountStatus: "on", Name: "console"},
+		wanted:  ServiceAccount{},
+	},
+	{
+		name:     "disabled",
+		current:  madmin.InfoServiceAccountResp{AccountStatus: "on"},
+		wanted:   ServiceAccount{Disabled: true},
+		expected: &madmin.UpdateServiceAccountReq{NewStatus: "off"},
+	},
+	{
+		name:     "enabled and renamed",
+		current:  madmin.InfoServiceAccountResp{AccountStatus: "off", Name: "old"},
+		wanted:   ServiceAccount{Name: "ci"},
+		expected: &madmin.UpdateServiceAccountReq{NewStatus: "on", NewName: "ci"},
+	},
+	{
+		name:    "same expiration",
+		current: madmin.InfoServiceAccountResp{AccountStatus: "on", Expiration: &saExpiration},
+		wanted:  ServiceAccount{Expiration: &saExpiration},
+	},
+	{
+		name:     "new expiration",
+		current:  madmin.InfoServiceAccountResp{AccountStatus: "on", Expiration: &saExpiration},
+		wanted:   ServiceAccount{Expiration: &saLater},
+		expected: &madmin.UpdateServiceAccountReq{NewExpiration: &saLater},
+	},
+	{
+		name:     "expiration removed",
+		current:  madmin.InfoServiceAccountResp{AccountStatus: "on", Expiration: &saExpiration},
+		wanted:   ServiceAccount{},
+		expected: &madmin.UpdateServiceAccountReq{NewExpiration: &noExpiration},
+	},
+}
+
+func Test_decideServiceAccount(t *testing.T) {
+	for _, entry := range decideServiceAccountEntries {
+		t.Run(entry.name, func(t *testing.T) {
+			assert.Equal(t, entry.expected, decideServiceAccount(entry.current, entry.wanted))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package minio
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// TemporaryCredentials are issued by the STS API of MinIO, they carry the policies of their user.
+type TemporaryCredentials struct {
+	AccessKey, SecretKey, SessionToken string
+	Expiration                         time.Time
+}
+
+// AssumeRole issues temporary credentials valid for duration with the permissions of the user.
+// The password of the user is reset to a random one first, it is never known outside of the call.
+func (c *client) AssumeRole(
+	ctx context.Context, user string, duration time.Duration,
+) (TemporaryCredentials, error) {
+	password, err := GenerateSecretKey(0, nil)
+	if err != nil {
+		return TemporaryCredentials{}, err
+	}
+	if err := c.SetUser(ctx, user, string(password), madmin.AccountEnabled); err != nil {
+		return TemporaryCredentials{}, err
+	}
+	transport := c.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	role := &credentials.STSAssumeRole{
+		// the credentials API does not take a context, the transport carries it
+		Client:      &http.Client{Transport: contextTransport{ctx: ctx, RoundTripper: transport}},
+		STSEndpoint: c.EndpointURL().String(),
+		Options: credentials.STSAssumeRoleOptions{
+			AccessKey: user, SecretKey: string(password), Location: c.region,
+			DurationSeconds: int(duration.Seconds()),
+		},
+	}
+	value, err := role.Retrieve()
+	if err != nil {
+		return TemporaryCredentials{}, err
+	}
+	return TemporaryCredentials{
+		AccessKey: value.AccessKeyID, SecretKey: value.SecretAccessKey,
+		SessionToken: value.SessionToken, Expiration: value.Expiration,
+	}, nil
+}
+
+// contextTransport attaches the context to the requests it sends.
+type contextTransport struct {
+	ctx context.Context
+	http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.RoundTripper.RoundTrip(req.WithContext(t.ctx))
+}
@@ -0,0 +1,55 @@
+package minio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const assumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>TEMPORARY</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2030-01-02T03:04:05Z</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+func TestClient_AssumeRole(t *testing.T) {
+	reset := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the password of the user is reset before the credentials are requested
+		if strings.HasSuffix(r.URL.Path, "/add-user") && r.URL.Query().Get("accessKey") == "user" {
+			reset = true
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !reset || r.Form.Get("Action") != "AssumeRole" || r.Form.Get("DurationSeconds") != "7200" ||
+			!strings.Contains(r.Header.Get("Authorization"), "Credential=user/") {
+			http.Error(w, fmt.Sprintf("unexpected request %v", r.Form), http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, assumeRoleResponse)
+	}))
+	defer server.Close()
+
+	c, err := NewClient(strings.TrimPrefix(server.URL, "http://"), "admin", "password")
+	require.NoError(t, err)
+	credentials, err := c.AssumeRole(t.Context(), "user", 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, TemporaryCredentials{
+		AccessKey: "TEMPORARY", SecretKey: "secret", SessionToken: "token",
+		Expiration: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, credentials)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+// SetupBucketWebhookWithManager registers the webhook for Bucket in the manager.
+func SetupBucketWebhookWithManager(mgr ctrl.Manager, naming minio.NamingStrategy) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&miniov1alpha1.Bucket{}).
+		WithValidator(&BucketCustomValidator{Naming: naming}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-minio-ixday-github-io-v1alpha1-bucket,mutating=false,failurePolicy=fail,sideEffects=None,groups=minio.ixday.github.io,resources=buckets,verbs=create;update,versions=v1alpha1,name=vbucket-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// BucketCustomValidator rejects the buckets the controller would fail to reconcile.
+type BucketCustomValidator struct {
+	Naming minio.NamingStrategy
+}
+
+var _ webhook.CustomValidator = &BucketCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Bucket.
+func (v *BucketCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	bucket, ok := obj.(*miniov1alpha1.Bucket)
+	if !ok {
+		return nil, fmt.Errorf("expected a Bucket object but got %T", obj)
+	}
+	return nil, v.validate(bucket)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Bucket.
+func (v *BucketCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	bucket, ok := newObj.(*miniov1alpha1.Bucket)
+	if !ok {
+		return nil, fmt.Errorf("expected a Bucket object for the newObj but got %T", newObj)
+	}
+	if bucket.DeletionTimestamp != nil {
+		// an invalid bucket being deleted must still be able to release its finalizer
+		return nil, nil
+	}
+	return nil, v.validate(bucket)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Bucket.
+func (v *BucketCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *BucketCustomValidator) validate(bucket *miniov1alpha1.Bucket) error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	// a name already recorded in the status is kept by the controller whatever the strategy says
+	switch {
+	case bucket.Status.BucketName != "":
+	case bucket.Spec.ExternalName != "":
+		if err := s3utils.CheckValidBucketNameStrict(bucket.Spec.ExternalName); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("externalName"), bucket.Spec.ExternalName, err.Error()))
+		}
+	default:
+		if name, err := v.Naming.BucketName(bucket.Namespace, bucket.Name); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), bucket.Name,
+				fmt.Sprintf("generated bucket name %q: %s", name, err)))
+		}
+	}
+
+	if bucket.Spec.Lifecycle != nil {
+		rules := spec.Child("lifecycle", "rules")
+		for i, rule := range bucket.Spec.Lifecycle.Rules {
+			if _, err := minio.NewLifecycle([]miniov1alpha1.LifecycleRule{rule}); err != nil {
+				errs = append(errs, field.Invalid(rules.Index(i), rule.ID, err.Error()))
+			}
+		}
+	}
+
+	if err := validateSecretTemplate(bucket.Spec.SecretTemplate); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("secretTemplate"), bucket.Spec.SecretTemplate, err.Error()))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(miniov1alpha1.GroupVersion.WithKind("Bucket").GroupKind(), bucket.Name, errs)
+}
+
+// validateSecretTemplate parses the templates and renders them once, to catch unknown placeholders.
+func validateSecretTemplate(spec *miniov1alpha1.SecretTemplate) error {
+	templates, err := minio.NewSecretTemplate(spec)
+	if err != nil {
+		return err
+	}
+	_, err = templates.Render(minio.SecretData{})
+	return err
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+)
+
+var _ = Describe("Bucket Webhook", func() {
+	var (
+		obj       *miniov1alpha1.Bucket
+		validator BucketCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &miniov1alpha1.Bucket{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+		}
+		validator = BucketCustomValidator{}
+	})
+
+	Context("When creating or updating Bucket under Validating Webhook", func() {
+		It("Should admit a valid bucket", func() {
+			Expect(validator.ValidateCreate(context.Background(), obj)).To(BeNil())
+		})
+
+		It("Should deny a generated name which is not a valid bucket name", func() {
+			obj.Namespace = "under_score"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("metadata.name"))
+		})
+
+		It("Should deny an invalid external name", func() {
+			obj.Spec.ExternalName = "UPPER"
+			_, err := validator.ValidateUpdate(context.Background(), obj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.externalName")))
+		})
+
+		It("Should keep admitting a bucket whose name is already recorded", func() {
+			obj.Name = strings.Repeat("a", 70) + "_"
+			obj.Status.BucketName = "default.data"
+			Expect(validator.ValidateUpdate(context.Background(), obj, obj)).To(BeNil())
+		})
+
+		It("Should admit any update of a bucket being deleted", func() {
+			obj.Spec.ExternalName = "UPPER"
+			now := metav1.Now()
+			obj.DeletionTimestamp = &now
+			Expect(validator.ValidateUpdate(context.Background(), obj, obj)).To(BeNil())
+		})
+
+		It("Should locate the invalid lifecycle rules", func() {
+			obj.Spec.Lifecycle = &miniov1alpha1.BucketLifecycle{Rules: []miniov1alpha1.LifecycleRule{
+				{ID: "logs", ExpirationDays: 30},
+				{ID: "noop", Prefix: "tmp/"},
+			}}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.lifecycle.rules[1]")))
+		})
+
+		It("Should deny a secret template overriding the credentials or using unknown placeholders", func() {
+			obj.Spec.SecretTemplate = &miniov1alpha1.SecretTemplate{
+				Presets: []miniov1alpha1.SecretPreset{miniov1alpha1.PresetAWSEnv},
+			}
+			Expect(validator.ValidateCreate(context.Background(), obj)).To(BeNil())
+
+			obj.Spec.SecretTemplate.Data = map[string]string{"password": "{{ .Password }}"}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.secretTemplate")))
+
+			obj.Spec.SecretTemplate.Data = map[string]string{"url": "{{ .Unknown }}"}
+			_, err = validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.secretTemplate")))
+		})
+	})
+})
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+	"github.com/IxDay/internal/minio"
+)
+
+// SetupPolicyWebhookWithManager registers the webhook for Policy in the manager.
+func SetupPolicyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&miniov1alpha1.Policy{}).
+		WithValidator(&PolicyCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-minio-ixday-github-io-v1alpha1-policy,mutating=false,failurePolicy=fail,sideEffects=None,groups=minio.ixday.github.io,resources=policies,verbs=create;update,versions=v1alpha1,name=vpolicy-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PolicyCustomValidator rejects the statements MinIO would not accept.
+type PolicyCustomValidator struct{}
+
+var _ webhook.CustomValidator = &PolicyCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Policy.
+func (v *PolicyCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	policy, ok := obj.(*miniov1alpha1.Policy)
+	if !ok {
+		return nil, fmt.Errorf("expected a Policy object but got %T", obj)
+	}
+	return nil, v.validate(policy)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Policy.
+func (v *PolicyCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	policy, ok := newObj.(*miniov1alpha1.Policy)
+	if !ok {
+		return nil, fmt.Errorf("expected a Policy object for the newObj but got %T", newObj)
+	}
+	if policy.DeletionTimestamp != nil {
+		// an invalid policy being deleted must still be able to release its finalizer
+		return nil, nil
+	}
+	return nil, v.validate(policy)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Policy.
+func (v *PolicyCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PolicyCustomValidator) validate(policy *miniov1alpha1.Policy) error {
+	var errs field.ErrorList
+	statements := field.NewPath("spec", "statements")
+
+	// the actual bucket name is only known once the bucket is reconciled,
+	// the name of the resource is enough to validate the resources though
+	for i, statement := range policy.Spec.Statements {
+		_, err := minio.NewStatement(policy.Spec.BucketName, statement)
+		var statementErr *minio.StatementError
+		switch {
+		case errors.As(err, &statementErr):
+			path := statements.Index(i).Child(statementErr.Field)
+			if names := strings.Split(statementErr.Field, "."); len(names) > 1 {
+				path = statements.Index(i).Child(names[0], names[1:]...)
+			}
+			if statementErr.Index >= 0 {
+				path = path.Index(statementErr.Index)
+			}
+			errs = append(errs, field.Invalid(path, statementErr.Value, statementErr.Err.Error()))
+		case err != nil:
+			errs = append(errs, field.Invalid(statements.Index(i), statement, err.Error()))
+		}
+	}
+
+	if document := policy.Spec.Document; document != "" {
+		if _, err := minio.ParseDocument(document); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "document"), document, err.Error()))
+		}
+	}
+
+	if err := validateSecretTemplate(policy.Spec.SecretTemplate); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "secretTemplate"), policy.Spec.SecretTemplate, err.Error()))
+	}
+
+	// the secrets of the keys would overwrite each other
+	secretName := policy.Spec.SecretName
+	if secretName == "" {
+		secretName = policy.Name
+	}
+	secretNames := map[string]bool{secretName: true}
+	for i, key := range policy.Spec.AccessKeys {
+		name := key.SecretNameFor(policy.Name)
+		if secretNames[name] {
+			errs = append(errs, field.Duplicate(field.NewPath("spec", "accessKeys").Index(i).Child("secretName"), name))
+		}
+		secretNames[name] = true
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(miniov1alpha1.GroupVersion.WithKind("Policy").GroupKind(), policy.Name, errs)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	miniov1alpha1 "github.com/IxDay/api/v1alpha1"
+)
+
+var _ = Describe("Policy Webhook", func() {
+	var (
+		obj       *miniov1alpha1.Policy
+		validator PolicyCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &miniov1alpha1.Policy{
+			ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "default"},
+			Spec: miniov1alpha1.PolicySpec{
+				BucketName: "data",
+				Statements: []miniov1alpha1.Statement{{
+					Effect:   "Allow",
+					SubPaths: []string{"logs/*"},
+					Actions:  []string{"s3:GetObject", "s3:ListBucket"},
+				}},
+			},
+		}
+		validator = PolicyCustomValidator{}
+	})
+
+	Context("When creating or updating Policy under Validating Webhook", func() {
+		It("Should admit a valid policy", func() {
+			Expect(validator.ValidateCreate(context.Background(), obj)).To(BeNil())
+		})
+
+		It("Should locate an invalid action", func() {
+			obj.Spec.Statements[0].Actions[1] = "s3:DoEverything"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.statements[0].actions[1]")))
+		})
+
+		It("Should locate an invalid sub path", func() {
+			obj.Spec.Statements = append(obj.Spec.Statements, miniov1alpha1.Statement{
+				Effect: "Deny", SubPaths: []string{"/absolute"}, Actions: []string{"s3:PutObject"},
+			})
+			_, err := validator.ValidateUpdate(context.Background(), obj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.statements[1].subPaths[0]")))
+		})
+
+		It("Should admit any update of a policy being deleted", func() {
+			obj.Spec.Statements[0].Actions[1] = "s3:DoEverything"
+			now := metav1.Now()
+			obj.DeletionTimestamp = &now
+			Expect(validator.ValidateUpdate(context.Background(), obj, obj)).To(BeNil())
+		})
+
+		It("Should locate an invalid condition", func() {
+			obj.Spec.Statements[0].Conditions = &miniov1alpha1.StatementConditions{
+				SourceIPs: []string{"10.0.0.0/8", "not-an-ip"},
+			}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.statements[0].conditions.sourceIPs[1]")))
+		})
+
+		It("Should deny an invalid policy document", func() {
+			obj.Spec.Statements = nil
+			obj.Spec.Document = `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:Nope"]}]}`
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.document")))
+		})
+
+		It("Should deny a policy document carrying its own ID", func() {
+			obj.Spec.Statements = nil
+			obj.Spec.Document = `{"ID": "reader", "Version": "2012-10-17", "Statement": []}`
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("document ID is reserved")))
+		})
+
+		It("Should deny access keys sharing a secret", func() {
+			obj.Spec.AccessKeys = []miniov1alpha1.AccessKey{{Name: "ci"}, {Name: "backup", SecretName: "reader-ci"}}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.accessKeys[1].secretName")))
+
+			obj.Spec.AccessKeys = []miniov1alpha1.AccessKey{{Name: "ci", SecretName: "reader"}}
+			_, err = validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.accessKeys[0].secretName")))
+		})
+	})
+})